@@ -0,0 +1,313 @@
+// package automaton runs cellular automata: grids where every cell's next
+// state depends on how many of its neighbours are occupied.
+//
+// Day four is one, with the rule that rolls with fewer than four of their
+// eight neighbours occupied get removed:
+//
+//	a := automaton.Automaton{
+//		Neighbourhood: automaton.Moore(1),
+//		Rule:          automaton.Threshold(automaton.Count{Min: 4, Max: 8}, automaton.None),
+//	}
+//	result, err := a.Run(grid, 0)
+package automaton
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Neighbourhood is the offsets of a cell's neighbours from it, as row and
+// column. Any offsets will do, so long as they aren't 0, 0.
+type Neighbourhood [][2]int
+
+// Moore is the cells within r steps in any direction, including diagonally:
+// the eight around a cell when r is 1.
+func Moore(r int) Neighbourhood {
+	var n Neighbourhood
+	for dr := -r; dr <= r; dr++ {
+		for dc := -r; dc <= r; dc++ {
+			if dr != 0 || dc != 0 {
+				n = append(n, [2]int{dr, dc})
+			}
+		}
+	}
+	return n
+}
+
+// VonNeumann is the cells within r steps up, down, left and right: the four
+// next to a cell when r is 1.
+func VonNeumann(r int) Neighbourhood {
+	var n Neighbourhood
+	for dr := -r; dr <= r; dr++ {
+		for dc := -r; dc <= r; dc++ {
+			if (dr != 0 || dc != 0) && abs(dr)+abs(dc) <= r {
+				n = append(n, [2]int{dr, dc})
+			}
+		}
+	}
+	return n
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// Rule gives the next state of a cell from whether it's occupied now and how
+// many of its neighbours are.
+type Rule func(occupied bool, neighbours int) bool
+
+// Count is the neighbour counts from Min to Max, including both. It's empty
+// if Min > Max.
+type Count struct {
+	Min, Max int
+}
+
+// None is the Count that nothing is in.
+var None = Count{1, 0}
+
+func (c Count) contains(n int) bool {
+	return c.Min <= n && n <= c.Max
+}
+
+// Threshold is the rule where occupied cells stay occupied if their count
+// is in survive, and empty cells become occupied if their count is in birth.
+func Threshold(survive, birth Count) Rule {
+	return func(occupied bool, neighbours int) bool {
+		if occupied {
+			return survive.contains(neighbours)
+		}
+		return birth.contains(neighbours)
+	}
+}
+
+// LifeLike parses a rule written like B3/S23 (Conway's life), listing the
+// counts at which empty cells become occupied and at which occupied cells
+// stay that way.
+func LifeLike(spec string) (Rule, error) {
+	b, s, ok := strings.Cut(spec, "/")
+	if !ok {
+		return nil, fmt.Errorf("rule %q: want B.../S...", spec)
+	}
+	birth, err := counts(b, "B")
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", spec, err)
+	}
+	survive, err := counts(s, "S")
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", spec, err)
+	}
+	return func(occupied bool, neighbours int) bool {
+		if neighbours > 9 {
+			return false
+		}
+		if occupied {
+			return survive[neighbours]
+		}
+		return birth[neighbours]
+	}, nil
+}
+
+func counts(s, prefix string) ([10]bool, error) {
+	var set [10]bool
+	digits, ok := strings.CutPrefix(strings.ToUpper(s), prefix)
+	if !ok {
+		return set, fmt.Errorf("%q doesn't start with %s", s, prefix)
+	}
+	for _, d := range digits {
+		n, err := strconv.Atoi(string(d))
+		if err != nil {
+			return set, fmt.Errorf("%q: %c isn't a count", s, d)
+		}
+		set[n] = true
+	}
+	return set, nil
+}
+
+// Edge says what's past the edge of the grid.
+type Edge int
+
+const (
+	// Empty cells, all the way out.
+	Empty Edge = iota
+	// Wrap around to the other side.
+	Wrap
+	// Mirror the grid, so the cell just past an edge is the one on it.
+	Mirror
+)
+
+func (e Edge) String() string {
+	switch e {
+	case Empty:
+		return "empty"
+	case Wrap:
+		return "wrap"
+	case Mirror:
+		return "mirror"
+	}
+	return fmt.Sprintf("Edge(%d)", int(e))
+}
+
+// resolve finds which of n cells i refers to, or returns false if it's off
+// the edge and empty.
+func (e Edge) resolve(i, n int) (int, bool) {
+	if i >= 0 && i < n {
+		return i, true
+	}
+	switch e {
+	case Wrap:
+		return (i%n + n) % n, true
+	case Mirror:
+		i = (i%(2*n) + 2*n) % (2 * n)
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// Update says when cells see their neighbours' new states.
+type Update int
+
+const (
+	// Synchronous updates work out every cell's next state from the grid
+	// as it was, then change them all at once.
+	Synchronous Update = iota
+	// Asynchronous updates change each cell as soon as its next state is
+	// known, going along the rows from the top left, so later cells see
+	// the changes to earlier ones in the same step.
+	Asynchronous
+)
+
+// Automaton is the rules for how a grid changes.
+type Automaton struct {
+	Neighbourhood Neighbourhood
+	Rule          Rule
+	Edge          Edge
+	Update        Update
+}
+
+// Neighbours counts the occupied neighbours of the cell at r, c.
+func (a *Automaton) Neighbours(g *Grid, r, c int) int {
+	n := 0
+	for _, off := range a.Neighbourhood {
+		nr, ok := a.Edge.resolve(r+off[0], g.rows)
+		if !ok {
+			continue
+		}
+		nc, ok := a.Edge.resolve(c+off[1], g.cols)
+		if !ok {
+			continue
+		}
+		if g.cells[nr*g.cols+nc] {
+			n++
+		}
+	}
+	return n
+}
+
+// Step returns the grid after one step, and how many cells changed. It
+// doesn't change g. It returns Validate's error if the automaton can't run.
+func (a *Automaton) Step(g *Grid) (*Grid, int, error) {
+	if err := a.Validate(); err != nil {
+		return nil, 0, err
+	}
+	next, changed := a.step(g)
+	return next, changed, nil
+}
+
+// step is Step for an automaton that's known to be valid.
+func (a *Automaton) step(g *Grid) (*Grid, int) {
+	var (
+		next    = g.Clone()
+		from    = g
+		changed = 0
+	)
+	if a.Update == Asynchronous {
+		from = next
+	}
+	for r := range g.rows {
+		for c := range g.cols {
+			i := r*g.cols + c
+			state := a.Rule(from.cells[i], a.Neighbours(from, r, c))
+			if state != next.cells[i] {
+				next.cells[i] = state
+				changed++
+			}
+		}
+	}
+	return next, changed
+}
+
+// Result is how a run finished.
+type Result struct {
+	// Grid is the last grid.
+	Grid *Grid
+	// Steps is how many steps were taken to get to Grid.
+	Steps int
+	// Period is 1 if Grid doesn't change any more, and more than 1 if
+	// it's in a cycle that long, so it'll come round again after Period
+	// steps. It's 0 if the run stopped before either happened.
+	Period int
+}
+
+// Stable reports whether the grid stopped changing.
+func (r Result) Stable() bool { return r.Period == 1 }
+
+// Cycling reports whether the grid got into a cycle of more than one grid.
+func (r Result) Cycling() bool { return r.Period > 1 }
+
+// ErrNoRule is returned by Validate for an automaton without a rule.
+var ErrNoRule = errors.New("automaton has no rule")
+
+// Validate checks the automaton can run.
+func (a *Automaton) Validate() error {
+	if a.Rule == nil {
+		return ErrNoRule
+	}
+	for _, off := range a.Neighbourhood {
+		if off == [2]int{} {
+			return errors.New("a cell can't be its own neighbour")
+		}
+	}
+	if a.Edge < Empty || a.Edge > Mirror {
+		return fmt.Errorf("unknown edge %v", a.Edge)
+	}
+	if a.Update < Synchronous || a.Update > Asynchronous {
+		return fmt.Errorf("unknown update %d", a.Update)
+	}
+	return nil
+}
+
+// Run steps the grid until it stops changing or repeats one it's been
+// before, or for at most maxSteps steps if maxSteps isn't 0. It remembers
+// every grid it sees to spot cycles, so a run that doesn't settle down can
+// use a lot of memory without a limit. It returns an error if the automaton
+// can't run or maxSteps is negative.
+func (a *Automaton) Run(g *Grid, maxSteps int) (Result, error) {
+	if err := a.Validate(); err != nil {
+		return Result{}, err
+	}
+	if maxSteps < 0 {
+		return Result{}, fmt.Errorf("can't run for %d steps", maxSteps)
+	}
+	seen := map[string]int{g.key(): 0}
+	for step := 1; maxSteps == 0 || step <= maxSteps; step++ {
+		next, changed := a.step(g)
+		if changed == 0 {
+			return Result{Grid: g, Steps: step - 1, Period: 1}, nil
+		}
+		g = next
+		key := g.key()
+		if first, ok := seen[key]; ok {
+			return Result{Grid: g, Steps: step, Period: step - first}, nil
+		}
+		seen[key] = step
+	}
+	return Result{Grid: g, Steps: maxSteps}, nil
+}
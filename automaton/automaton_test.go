@@ -0,0 +1,223 @@
+package automaton
+
+import (
+	"errors"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) *Grid {
+	t.Helper()
+	g, err := Parse(s, '#')
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func mustRun(t *testing.T, a Automaton, g *Grid, maxSteps int) Result {
+	t.Helper()
+	result, err := a.Run(g, maxSteps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func life(t *testing.T) Rule {
+	t.Helper()
+	rule, err := LifeLike("B3/S23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rule
+}
+
+func TestNeighbourhoods(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		n    Neighbourhood
+		want int
+	}{
+		{"Moore(1)", Moore(1), 8},
+		{"Moore(2)", Moore(2), 24},
+		{"VonNeumann(1)", VonNeumann(1), 4},
+		{"VonNeumann(2)", VonNeumann(2), 12},
+	} {
+		if len(c.n) != c.want {
+			t.Errorf("%s has %d cells, want %d", c.name, len(c.n), c.want)
+		}
+	}
+}
+
+func TestEdges(t *testing.T) {
+	g := mustParse(t, "#..\n...\n..#\n")
+	for _, c := range []struct {
+		edge Edge
+		want int
+	}{
+		// The # at 0, 0 is too far away.
+		{Empty, 0},
+		// Unless it's round the corner.
+		{Wrap, 1},
+		// 2, 2 is its own neighbour three times over, past the bottom,
+		// the right and the corner.
+		{Mirror, 3},
+	} {
+		a := Automaton{Neighbourhood: Moore(1), Rule: life(t), Edge: c.edge}
+		if got := a.Neighbours(g, 2, 2); got != c.want {
+			t.Errorf("%v: Neighbours(2, 2) = %d, want %d", c.edge, got, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		edge   Edge
+		i, n   int
+		want   int
+		wantOK bool
+	}{
+		{Empty, -1, 3, 0, false},
+		{Empty, 3, 3, 0, false},
+		{Wrap, -1, 3, 2, true},
+		{Wrap, 7, 3, 1, true},
+		{Mirror, -1, 3, 0, true},
+		{Mirror, -3, 3, 2, true},
+		{Mirror, 3, 3, 2, true},
+		{Mirror, 5, 3, 0, true},
+		{Mirror, 6, 3, 0, true},
+	} {
+		got, ok := c.edge.resolve(c.i, c.n)
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("%v.resolve(%d, %d) = %d, %v, want %d, %v", c.edge, c.i, c.n, got, ok, c.want, c.wantOK)
+		}
+	}
+}
+
+func TestRunStable(t *testing.T) {
+	// Day four's example.
+	g, err := Parse(`..@@.@@@@.
+@@@.@.@.@@
+@@@@@.@.@@
+@.@@@@..@.
+@@.@@@@.@@
+.@@@@@@@.@
+.@.@.@.@@@
+@.@@@.@@@@
+.@@@@@@@@.
+@.@.@@@.@.
+`, '@')
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := Automaton{
+		Neighbourhood: Moore(1),
+		Rule:          Threshold(Count{Min: 4, Max: 8}, None),
+	}
+	first, changed, err := a.Step(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 13 || g.Count()-first.Count() != 13 {
+		t.Errorf("first step changed %d cells, want 13", changed)
+	}
+	result := mustRun(t, a, g, 0)
+	if !result.Stable() {
+		t.Fatalf("got period %d, want a stable grid", result.Period)
+	}
+	if removed := g.Count() - result.Grid.Count(); removed != 43 {
+		t.Errorf("removed %d rolls, want 43", removed)
+	}
+
+	// Changing cells as it goes can only remove more each step, so it
+	// gets to the same place at least as fast.
+	a.Update = Asynchronous
+	async := mustRun(t, a, g, 0)
+	if !async.Grid.Equal(result.Grid) || async.Steps > result.Steps {
+		t.Errorf("asynchronous run took %d steps to get to\n%v\nwant at most %d to get to\n%v", async.Steps, async.Grid, result.Steps, result.Grid)
+	}
+}
+
+func TestRunCycles(t *testing.T) {
+	blinker := mustParse(t, ".....\n..#..\n..#..\n..#..\n.....\n")
+	a := Automaton{Neighbourhood: Moore(1), Rule: life(t)}
+	result := mustRun(t, a, blinker, 0)
+	if result.Period != 2 || result.Steps != 2 || !result.Grid.Equal(blinker) {
+		t.Errorf("blinker: got period %d after %d steps, want 2 after 2", result.Period, result.Steps)
+	}
+
+	// A glider on a torus comes back where it started after it's moved
+	// all the way across, one cell every four steps.
+	glider := mustParse(t, ".#....\n..#...\n###...\n......\n......\n......\n")
+	a.Edge = Wrap
+	result = mustRun(t, a, glider, 0)
+	if result.Period != 24 || !result.Grid.Equal(glider) {
+		t.Errorf("glider: got period %d after %d steps, want 24", result.Period, result.Steps)
+	}
+
+	// Without wrapping it turns into a block in the corner.
+	a.Edge = Empty
+	if result = mustRun(t, a, glider, 0); !result.Stable() || result.Grid.Count() != 4 {
+		t.Errorf("glider: got period %d and\n%v\nwant a block", result.Period, result.Grid)
+	}
+
+	if result = mustRun(t, a, glider, 3); result.Period != 0 || result.Steps != 3 {
+		t.Errorf("glider: ran %d steps with period %d, want 3 and 0", result.Steps, result.Period)
+	}
+}
+
+func TestLifeLike(t *testing.T) {
+	rule, err := LifeLike("b36/s23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		occupied   bool
+		neighbours int
+		want       bool
+	}{
+		{false, 3, true},
+		{false, 6, true},
+		{false, 2, false},
+		{true, 2, true},
+		{true, 6, false},
+	} {
+		if got := rule(c.occupied, c.neighbours); got != c.want {
+			t.Errorf("HighLife(%v, %d) = %v, want %v", c.occupied, c.neighbours, got, c.want)
+		}
+	}
+	for _, bad := range []string{"", "B3", "S23/B3", "B3/S2x"} {
+		if _, err := LifeLike(bad); err == nil {
+			t.Errorf("LifeLike(%q): no error", bad)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, a := range []Automaton{
+		{},
+		{Rule: Threshold(None, None), Neighbourhood: Neighbourhood{{0, 0}}},
+		{Rule: Threshold(None, None), Edge: 3},
+	} {
+		if err := a.Validate(); err == nil {
+			t.Errorf("%+v: no error", a)
+		}
+	}
+	a := Automaton{Neighbourhood: VonNeumann(1), Rule: Threshold(None, None), Edge: Mirror, Update: Asynchronous}
+	if err := a.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	g := mustParse(t, "#.\n.#\n")
+	var a Automaton
+	if _, _, err := a.Step(g); !errors.Is(err, ErrNoRule) {
+		t.Errorf("Step without a rule: got %v, want %v", err, ErrNoRule)
+	}
+	if _, err := a.Run(g, 0); !errors.Is(err, ErrNoRule) {
+		t.Errorf("Run without a rule: got %v, want %v", err, ErrNoRule)
+	}
+	a.Rule = life(t)
+	if _, err := a.Run(g, -1); err == nil {
+		t.Error("Run(g, -1): no error")
+	}
+}
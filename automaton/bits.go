@@ -0,0 +1,236 @@
+package automaton
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// BitGrid is a Grid packed 64 cells to a word, for running automata with
+// the Moore(1) neighbourhood, empty edges and synchronous updates a word at
+// a time instead of a cell at a time. Cell c of a row is bit c%64 of word
+// c/64, and the bits past the last column are always 0.
+type BitGrid struct {
+	rows, cols int
+	words      int // per row
+	bits       []uint64
+}
+
+// NewBitGrid returns an empty grid.
+func NewBitGrid(rows, cols int) *BitGrid {
+	words := (cols + 63) / 64
+	return &BitGrid{rows: rows, cols: cols, words: words, bits: make([]uint64, rows*words)}
+}
+
+// BitsFromBools packs cells into a grid. The rows all have to be the same
+// length.
+func BitsFromBools(cells [][]bool) (*BitGrid, error) {
+	cols := 0
+	if len(cells) > 0 {
+		cols = len(cells[0])
+	}
+	g := NewBitGrid(len(cells), cols)
+	for r, row := range cells {
+		if len(row) != cols {
+			return nil, fmt.Errorf("row %d has %d cells, but row 0 has %d", r, len(row), cols)
+		}
+		for c, occupied := range row {
+			if occupied {
+				g.Set(r, c, true)
+			}
+		}
+	}
+	return g, nil
+}
+
+func (g *BitGrid) Rows() int { return g.rows }
+func (g *BitGrid) Cols() int { return g.cols }
+
+func (g *BitGrid) row(r int) []uint64 {
+	return g.bits[r*g.words : (r+1)*g.words]
+}
+
+// Get reports whether the cell at r, c is occupied. It panics if that's off
+// the grid.
+func (g *BitGrid) Get(r, c int) bool {
+	g.check(r, c)
+	return g.row(r)[c/64]&(1<<(c%64)) != 0
+}
+
+// Set occupies or empties the cell at r, c.
+func (g *BitGrid) Set(r, c int, occupied bool) {
+	g.check(r, c)
+	if occupied {
+		g.row(r)[c/64] |= 1 << (c % 64)
+	} else {
+		g.row(r)[c/64] &^= 1 << (c % 64)
+	}
+}
+
+func (g *BitGrid) check(r, c int) {
+	if r < 0 || c < 0 || r >= g.rows || c >= g.cols {
+		panic(fmt.Sprintf("%d, %d is off a %dx%d grid", r, c, g.rows, g.cols))
+	}
+}
+
+// Count returns how many cells are occupied.
+func (g *BitGrid) Count() int {
+	n := 0
+	for _, w := range g.bits {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (g *BitGrid) Clone() *BitGrid {
+	next := *g
+	next.bits = append([]uint64(nil), g.bits...)
+	return &next
+}
+
+// Equal reports whether two grids are the same size with the same cells
+// occupied.
+func (g *BitGrid) Equal(other *BitGrid) bool {
+	if g.rows != other.rows || g.cols != other.cols {
+		return false
+	}
+	for i, w := range g.bits {
+		if w != other.bits[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Bools copies the grid out into rows.
+func (g *BitGrid) Bools() [][]bool {
+	cells := make([][]bool, g.rows)
+	for r := range cells {
+		cells[r] = make([]bool, g.cols)
+		for c := range cells[r] {
+			cells[r][c] = g.Get(r, c)
+		}
+	}
+	return cells
+}
+
+// Diff calls f with the position of every cell that's occupied in g but not
+// in other, which has to be the same size.
+func (g *BitGrid) Diff(other *BitGrid, f func(r, c int)) {
+	for i, w := range g.bits {
+		for w &^= other.bits[i]; w != 0; w &= w - 1 {
+			f(i/g.words, i%g.words*64+bits.TrailingZeros64(w))
+		}
+	}
+}
+
+// String draws the grid with # for occupied cells and . for empty ones.
+func (g *BitGrid) String() string {
+	var sb strings.Builder
+	for r := range g.rows {
+		for c := range g.cols {
+			if g.Get(r, c) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// slicedCounts is how many of the eight neighbours of 64 cells are occupied,
+// sliced into bits: bit i of n[k] is bit k of the count for cell i.
+type slicedCounts [4]uint64
+
+// add adds one neighbour, one bit per cell, rippling the carries up.
+func (n *slicedCounts) add(x uint64) {
+	for k := range n {
+		n[k], x = n[k]^x, n[k]&x
+	}
+}
+
+// equal returns the cells whose count is v.
+func (n *slicedCounts) equal(v int) uint64 {
+	m := ^uint64(0)
+	for k, plane := range n {
+		if v&(1<<k) != 0 {
+			m &= plane
+		} else {
+			m &^= plane
+		}
+	}
+	return m
+}
+
+// count adds up the neighbours of the cells in word i of row r.
+func (g *BitGrid) count(r, i int) slicedCounts {
+	var n slicedCounts
+	for dr := -1; dr <= 1; dr++ {
+		if r+dr < 0 || r+dr >= g.rows {
+			continue
+		}
+		row := g.row(r + dr)
+		var (
+			w    = row[i]
+			west = w << 1 // bit c is now cell c-1
+			east = w >> 1 // and cell c+1
+		)
+		if i > 0 {
+			west |= row[i-1] >> 63
+		}
+		if i+1 < g.words {
+			east |= row[i+1] << 63
+		}
+		n.add(west)
+		n.add(east)
+		if dr != 0 {
+			n.add(w)
+		}
+	}
+	return n
+}
+
+// Step returns the grid after one step of the rule over the eight cells
+// around each one, with nothing past the edges, and how many cells changed.
+// It doesn't change g.
+func (g *BitGrid) Step(rule Rule) (*BitGrid, int) {
+	// Which counts occupy a cell, for occupied and empty cells.
+	var survive, birth []int
+	for v := range 9 {
+		if rule(true, v) {
+			survive = append(survive, v)
+		}
+		if rule(false, v) {
+			birth = append(birth, v)
+		}
+	}
+	var (
+		next    = NewBitGrid(g.rows, g.cols)
+		last    = ^uint64(0) >> ((64 - g.cols%64) % 64) // the columns in the last word
+		changed = 0
+	)
+	for r := range g.rows {
+		var (
+			from = g.row(r)
+			to   = next.row(r)
+		)
+		for i, w := range from {
+			n := g.count(r, i)
+			var s, b uint64
+			for _, v := range survive {
+				s |= n.equal(v)
+			}
+			for _, v := range birth {
+				b |= n.equal(v)
+			}
+			to[i] = w&s | ^w&b
+			if i == g.words-1 {
+				to[i] &= last
+			}
+			changed += bits.OnesCount64(to[i] ^ w)
+		}
+	}
+	return next, changed
+}
@@ -0,0 +1,92 @@
+package automaton
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestBitGridStep(t *testing.T) {
+	r := rand.New(rand.NewPCG(47, 47))
+	rules := map[string]Rule{
+		"life":     life(t),
+		"removal":  Threshold(Count{Min: 4, Max: 8}, None),
+		"anything": Threshold(Count{Min: 0, Max: 8}, Count{Min: 0, Max: 8}),
+		"eight":    Threshold(None, Count{Min: 8, Max: 8}),
+	}
+	// Widths either side of word boundaries, where the shifts carry
+	// between words.
+	for _, cols := range []int{1, 2, 63, 64, 65, 127, 128, 130} {
+		for _, rows := range []int{1, 2, 7} {
+			cells := make([][]bool, rows)
+			for i := range cells {
+				cells[i] = make([]bool, cols)
+				for j := range cells[i] {
+					cells[i][j] = r.IntN(2) == 0
+				}
+			}
+			g, err := FromBools(cells)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := BitsFromBools(cells)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(b.Bools(), cells) || b.Count() != g.Count() {
+				t.Fatalf("%dx%d: BitsFromBools(cells).Bools() isn't cells", rows, cols)
+			}
+			for name, rule := range rules {
+				a := Automaton{Neighbourhood: Moore(1), Rule: rule}
+				want, wantChanged, err := a.Step(g)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, gotChanged := b.Step(rule)
+				if gotChanged != wantChanged || !reflect.DeepEqual(got.Bools(), want.Bools()) {
+					t.Errorf("%dx%d %s: got %d changed to\n%v\nwant %d changed to\n%v", rows, cols, name, gotChanged, got, wantChanged, want)
+				}
+				if got.String() != want.String() {
+					t.Errorf("%dx%d %s: String() = %q, want %q", rows, cols, name, got.String(), want.String())
+				}
+
+				var diff [][2]int
+				b.Diff(got, func(r, c int) { diff = append(diff, [2]int{r, c}) })
+				for _, rc := range diff {
+					if !g.Get(rc[0], rc[1]) || want.Get(rc[0], rc[1]) {
+						t.Errorf("%dx%d %s: Diff gave %v, which wasn't emptied", rows, cols, name, rc)
+					}
+				}
+				emptied := 0
+				for r := range rows {
+					for c := range cols {
+						if g.Get(r, c) && !want.Get(r, c) {
+							emptied++
+						}
+					}
+				}
+				if len(diff) != emptied {
+					t.Errorf("%dx%d %s: Diff gave %d cells, want %d", rows, cols, name, len(diff), emptied)
+				}
+			}
+		}
+	}
+}
+
+func TestBitGridGetSet(t *testing.T) {
+	g := NewBitGrid(2, 70)
+	g.Set(1, 69, true)
+	g.Set(0, 3, true)
+	g.Set(0, 3, false)
+	if !g.Get(1, 69) || g.Get(0, 3) || g.Count() != 1 {
+		t.Errorf("got\n%v", g)
+	}
+	other := g.Clone()
+	other.Set(0, 0, true)
+	if g.Equal(other) || g.Get(0, 0) {
+		t.Error("changing a copy changed the original")
+	}
+	if _, err := BitsFromBools([][]bool{{true}, {}}); err == nil {
+		t.Error("ragged grid: no error")
+	}
+}
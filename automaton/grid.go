@@ -0,0 +1,137 @@
+package automaton
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Grid is a rectangle of cells that are either occupied or empty.
+type Grid struct {
+	rows, cols int
+	cells      []bool
+}
+
+// NewGrid returns an empty grid.
+func NewGrid(rows, cols int) *Grid {
+	return &Grid{rows: rows, cols: cols, cells: make([]bool, rows*cols)}
+}
+
+// FromBools copies cells into a grid. The rows all have to be the same
+// length.
+func FromBools(cells [][]bool) (*Grid, error) {
+	cols := 0
+	if len(cells) > 0 {
+		cols = len(cells[0])
+	}
+	g := NewGrid(len(cells), cols)
+	for r, row := range cells {
+		if len(row) != cols {
+			return nil, fmt.Errorf("row %d has %d cells, but row 0 has %d", r, len(row), cols)
+		}
+		copy(g.cells[r*cols:], row)
+	}
+	return g, nil
+}
+
+// Parse reads a grid from lines of text, where occupied cells are the byte
+// occupied and anything else is empty.
+func Parse(s string, occupied byte) (*Grid, error) {
+	var cells [][]bool
+	for line := range strings.Lines(s) {
+		line = strings.TrimRight(line, "\r\n")
+		row := make([]bool, len(line))
+		for i := range len(line) {
+			row[i] = line[i] == occupied
+		}
+		cells = append(cells, row)
+	}
+	return FromBools(cells)
+}
+
+func (g *Grid) Rows() int { return g.rows }
+func (g *Grid) Cols() int { return g.cols }
+
+// Get reports whether the cell at r, c is occupied. It panics if that's off
+// the grid.
+func (g *Grid) Get(r, c int) bool {
+	g.check(r, c)
+	return g.cells[r*g.cols+c]
+}
+
+// Set occupies or empties the cell at r, c.
+func (g *Grid) Set(r, c int, occupied bool) {
+	g.check(r, c)
+	g.cells[r*g.cols+c] = occupied
+}
+
+func (g *Grid) check(r, c int) {
+	if r < 0 || c < 0 || r >= g.rows || c >= g.cols {
+		panic(fmt.Sprintf("%d, %d is off a %dx%d grid", r, c, g.rows, g.cols))
+	}
+}
+
+// Count returns how many cells are occupied.
+func (g *Grid) Count() int {
+	n := 0
+	for _, c := range g.cells {
+		if c {
+			n++
+		}
+	}
+	return n
+}
+
+func (g *Grid) Clone() *Grid {
+	return &Grid{rows: g.rows, cols: g.cols, cells: append([]bool(nil), g.cells...)}
+}
+
+// Equal reports whether two grids are the same size with the same cells
+// occupied.
+func (g *Grid) Equal(other *Grid) bool {
+	if g.rows != other.rows || g.cols != other.cols {
+		return false
+	}
+	for i, c := range g.cells {
+		if c != other.cells[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Bools copies the grid out into rows.
+func (g *Grid) Bools() [][]bool {
+	cells := make([][]bool, g.rows)
+	for r := range cells {
+		cells[r] = append([]bool(nil), g.cells[r*g.cols:(r+1)*g.cols]...)
+	}
+	return cells
+}
+
+// String draws the grid with # for occupied cells and . for empty ones.
+func (g *Grid) String() string {
+	var sb strings.Builder
+	for i, c := range g.cells {
+		if c {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('.')
+		}
+		if (i+1)%g.cols == 0 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// key is a compact copy of the cells, for noticing when a grid comes round
+// again.
+func (g *Grid) key() string {
+	b := make([]byte, (len(g.cells)+7)/8)
+	for i, c := range g.cells {
+		if c {
+			b[i/8] |= 1 << (i % 8)
+		}
+	}
+	return string(b)
+}
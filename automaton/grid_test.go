@@ -0,0 +1,38 @@
+package automaton
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrid(t *testing.T) {
+	const s = "#..\n.#.\n##.\n"
+	g := mustParse(t, s)
+	if g.Rows() != 3 || g.Cols() != 3 || g.Count() != 4 {
+		t.Errorf("got %dx%d with %d occupied, want 3x3 with 4", g.Rows(), g.Cols(), g.Count())
+	}
+	if got := g.String(); got != s {
+		t.Errorf("String() = %q, want %q", got, s)
+	}
+	other, err := FromBools(g.Bools())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !other.Equal(g) {
+		t.Errorf("FromBools(Bools()) = %v, want %v", other, g)
+	}
+	other.Set(0, 2, true)
+	if other.Equal(g) || g.Get(0, 2) {
+		t.Error("changing a copy changed the original")
+	}
+	if !reflect.DeepEqual(other.Bools()[0], []bool{true, false, true}) {
+		t.Errorf("row 0 = %v", other.Bools()[0])
+	}
+	if g.key() == other.key() {
+		t.Error("different grids have the same key")
+	}
+
+	if _, err := Parse("##\n#\n", '#'); err == nil {
+		t.Error("ragged grid: no error")
+	}
+}
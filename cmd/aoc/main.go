@@ -0,0 +1,239 @@
+// aoc has tools for working on the other days, rather than a puzzle of its
+// own.
+//
+// Usage:
+//
+//	aoc parse -grammar 'lines = int % nl' inputs/example.txt
+//
+// parses its input (or stdin, if there aren't any files) with a grammar
+// written in the notation described on parse.Grammar, and prints the tree.
+// The grammar can also be read from a file with -grammar @path.
+//
+//	//go:generate go run github.com/pfcm/aoc25/cmd/aoc generate -grammar @devices.grammar -func parseDevices
+//
+// writes a Go parser for a grammar, which does exactly what the grammar's own
+// parser does but faster, to a file named after the function.
+//
+//	//go:generate go run github.com/pfcm/aoc25/cmd/aoc generate -decoder pointDecoder -func parsePoint
+//
+// does the same for a parse.Decoder held in a package level variable, using
+// Decoder.Generate. The types it decodes are only known to the package, so
+// this builds and runs a copy of the package with a main function of its own
+// that writes out the code.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal("usage: aoc parse|generate [flags]")
+	}
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "parse":
+		err = parseCmd(args)
+	case "generate":
+		err = generateCmd(args)
+	default:
+		err = fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readGrammar parses a grammar from a -grammar flag.
+func readGrammar(arg string) (*parse.Grammar, error) {
+	src := arg
+	if path, ok := strings.CutPrefix(src, "@"); ok {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		src = string(b)
+	}
+	return parse.ParseGrammar(src)
+}
+
+func parseCmd(args []string) error {
+	fs := flag.NewFlagSet("parse", flag.ExitOnError)
+	grammarFlag := fs.String("grammar", "", "the grammar, or @path to read it from a file")
+	fs.Parse(args)
+
+	g, err := readGrammar(*grammarFlag)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return parseInput(g, os.Stdin, "stdin")
+	}
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = parseInput(g, f, path)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseInput(g *parse.Grammar, r io.Reader, name string) error {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	tree, err := g.Parse(input)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	fmt.Print(tree)
+	return nil
+}
+
+func generateCmd(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	var (
+		grammarFlag = fs.String("grammar", "", "the grammar, or @path to read it from a file")
+		decoderFlag = fs.String("decoder", "", "a package level parse.Decoder variable to generate from instead of a grammar")
+		funcFlag    = fs.String("func", "", "name of the function to generate")
+		pkgFlag     = fs.String("package", os.Getenv("GOPACKAGE"), "package to generate it in, by default the one go generate is running for")
+		outFlag     = fs.String("o", "", "file to write, by default the function's name in snake case")
+	)
+	fs.Parse(args)
+	if *funcFlag == "" || *pkgFlag == "" {
+		return errors.New("generate needs -func and -package")
+	}
+	if (*grammarFlag == "") == (*decoderFlag == "") {
+		return errors.New("generate needs one of -grammar and -decoder")
+	}
+	out := *outFlag
+	if out == "" {
+		out = snakeCase(*funcFlag) + ".go"
+	}
+
+	var src bytes.Buffer
+	if *decoderFlag != "" {
+		if err := generateDecoder(&src, *decoderFlag, *pkgFlag, *funcFlag, out); err != nil {
+			return err
+		}
+		return os.WriteFile(out, src.Bytes(), 0o644)
+	}
+	g, err := readGrammar(*grammarFlag)
+	if err != nil {
+		return err
+	}
+	if err := g.Generate(&src, *pkgFlag, *funcFlag); err != nil {
+		return err
+	}
+	return os.WriteFile(out, src.Bytes(), 0o644)
+}
+
+// generateDecoder writes the code for the Decoder in the variable decoder of
+// the package in the current directory. It copies the package's files into a
+// temporary directory, leaving out its tests and the file out, which might not
+// compile any more, and turns it into a command that calls Generate. Everything
+// that used to call the generated function calls the Decoder's parser
+// instead.
+func generateDecoder(w io.Writer, decoder, pkg, name, out string) error {
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		return err
+	}
+	// It has to be inside the module, so that the imports resolve.
+	dir, err := os.MkdirTemp(".", ".aoc-generate-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if path == out || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		f.Name.Name = "main"
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				fn.Name.Name = "originalMain"
+			}
+		}
+		var src bytes.Buffer
+		if err := format.Node(&src, fset, f); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), src.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+
+	generator := fmt.Sprintf(`package main
+
+import (
+	"log"
+	"os"
+)
+
+var %[2]s = %[1]s.Parser()
+
+func main() {
+	if err := %[1]s.Generate(os.Stdout, %[3]q, %[2]q); err != nil {
+		log.Fatal(err)
+	}
+}
+`, decoder, name, pkg)
+	if err := os.WriteFile(filepath.Join(dir, "aoc_generate.go"), []byte(generator), 0o644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running the generator for %s: %w", decoder, err)
+	}
+	return nil
+}
+
+// snakeCase turns parseDevices into parse_devices.
+func snakeCase(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -11,6 +11,7 @@ import (
 	"slices"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
 var (
@@ -201,6 +202,9 @@ func (u *ufNode) Union(v *ufNode) *ufNode {
 	return u
 }
 
+// inputSyntax reads and prints a whole input, a box to a line.
+var inputSyntax = parse.LinesSyntax(parse.FormatSyntax[aoc25.IntVector[int]]("{x},{y},{z}"))
+
 func read(r io.Reader) ([]aoc25.IntVector[int], error) {
 	var (
 		results []aoc25.IntVector[int]
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+func TestInputSyntax(t *testing.T) {
+	parsetest.RoundTrip(t, inputSyntax, func(in []byte) ([]aoc25.IntVector[int], error) { return read(bytes.NewReader(in)) },
+		"inputs/example.txt", "inputs/input.txt")
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
 var (
@@ -141,6 +142,17 @@ type devices struct {
 	edges [][]int
 }
 
+// deviceLine is a line of the input: a device, and the devices its outputs
+// go to.
+type deviceLine struct {
+	Name    string
+	Outputs []string `sep:" "`
+}
+
+// inputSyntax reads and prints a whole input, a device to a line. The newline
+// is in the format so that the last output stops there.
+var inputSyntax = parse.ManySyntax(parse.FormatSyntax[deviceLine]("{name}: {outputs}\n"))
+
 func read(r io.Reader) (*devices, error) {
 	var (
 		names       []string
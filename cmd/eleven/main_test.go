@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+// lines turns the devices back into the lines they were read from.
+func (d *devices) lines() []deviceLine {
+	// The last device is "out", which doesn't have a line.
+	ls := make([]deviceLine, len(d.names)-1)
+	for i := range ls {
+		ls[i].Name = d.names[i]
+		for _, to := range d.edges[i] {
+			ls[i].Outputs = append(ls[i].Outputs, d.names[to])
+		}
+	}
+	return ls
+}
+
+func TestInputSyntax(t *testing.T) {
+	read := func(in []byte) ([]deviceLine, error) {
+		d, err := read(bytes.NewReader(in))
+		if err != nil {
+			return nil, err
+		}
+		return d.lines(), nil
+	}
+	parsetest.RoundTrip(t, inputSyntax, read, "inputs/example.txt", "inputs/example2.txt", "inputs/input.txt")
+}
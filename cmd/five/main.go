@@ -3,8 +3,11 @@ package main
 
 import (
 	"bufio"
+	"cmp"
+	"flag"
 	"fmt"
 	"io"
+	"iter"
 	"log"
 	"os"
 	"slices"
@@ -12,123 +15,239 @@ import (
 	"strings"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
+var queryFlag = flag.String("query", "", "comma separated `IDs` to look up instead of doing the two parts")
+
 func main() {
-	ranges, ids, err := read(os.Stdin)
+	flag.Parse()
+	scan := bufio.NewScanner(os.Stdin)
+	ranges, err := readRanges(scan)
 	if err != nil {
 		log.Fatal(err)
 	}
-	aoc25.PrintTiming("Sort input", func() string {
-		slices.SortFunc(ranges, func(a, b Range) int {
-			return a.compare(b)
-		})
-		return ":)"
+	if *queryFlag != "" {
+		// Only the answers go to stdout, so that they can be used by
+		// something else.
+		if err := query(os.Stdout, newIndex(ranges), *queryFlag); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	var idx *index
+	aoc25.PrintTiming("Merge ranges", func() int {
+		idx = newIndex(ranges)
+		return len(idx.merged)
 	})
-
-	aoc25.PrintTiming("Part one", func() int { return partOne(ranges, ids) })
-	aoc25.PrintTiming("Part two", func() uint64 { return partTwo(ranges) })
+	aoc25.PrintTiming("Part one", func() int {
+		n, err := partOne(idx, readIDs(scan))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return n
+	})
+	aoc25.PrintTiming("Part two", func() uint64 { return partTwo(idx) })
 }
 
-func partTwo(ranges []Range) uint64 {
-	var (
-		newRanges []Range
-		r         = ranges[0]
-	)
-	for _, r2 := range ranges[1:] {
-		if r.end >= r2.start {
-			// The ranges overlap, merge them.
-			r.end = max(r.end, r2.end) // max to handle r2 entirely within r
+// query looks up each of a comma separated list of IDs, saying whether it's
+// fresh and which ranges it's in.
+func query(w io.Writer, idx *index, ids string) error {
+	for s := range strings.SplitSeq(ids, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return err
+		}
+		covering := idx.covering(id)
+		if len(covering) == 0 {
+			fmt.Fprintf(w, "%d: spoiled\n", id)
 			continue
 		}
-		newRanges = append(newRanges, r)
-		r = r2
+		fmt.Fprintf(w, "%d: fresh, in", id)
+		for i, n := range covering {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			r := idx.ranges[n]
+			fmt.Fprintf(w, " %d-%d (range %d)", r.Start, r.End, n+1)
+		}
+		fmt.Fprintln(w)
 	}
-	newRanges = append(newRanges, r)
+	return nil
+}
 
+func partTwo(idx *index) uint64 {
 	count := uint64(0)
-	for _, r := range newRanges {
-		n := r.end - r.start + 1
+	for _, r := range idx.merged {
+		n := r.End - r.Start + 1
 		count += n
 	}
 	return count
 }
 
-func partOne(ranges []Range, ids []uint64) int {
+func partOne(idx *index, ids iter.Seq2[uint64, error]) (int, error) {
 	count := 0
-	for _, id := range ids {
-		for _, r := range ranges {
-			if r.contains(id) {
-				count++
-				break
-			}
+	for id, err := range ids {
+		if err != nil {
+			return 0, err
+		}
+		if idx.contains(id) {
+			count++
 		}
 	}
-	return count
+	return count, nil
+}
+
+// index is the ranges merged together where they overlap, sorted so that IDs
+// can be looked up with a binary search.
+type index struct {
+	// ranges are the original ranges, in the order they were read.
+	ranges []Range
+	// merged don't overlap and are in order.
+	merged []Range
+	// members are the indexes into ranges of the ones that went into
+	// each merged range.
+	members [][]int
+}
+
+func newIndex(ranges []Range) *index {
+	order := make([]int, len(ranges))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return ranges[a].compare(ranges[b])
+	})
+	idx := &index{ranges: ranges}
+	for _, i := range order {
+		r, last := ranges[i], len(idx.merged)-1
+		if last >= 0 && idx.merged[last].End >= r.Start {
+			// The ranges overlap, merge them.
+			idx.merged[last].End = max(idx.merged[last].End, r.End) // max to handle r entirely within the last one
+			idx.members[last] = append(idx.members[last], i)
+			continue
+		}
+		idx.merged = append(idx.merged, r)
+		idx.members = append(idx.members, []int{i})
+	}
+	return idx
+}
+
+// find returns the index of the merged range that id is in, or -1 if it
+// isn't in any.
+func (idx *index) find(id uint64) int {
+	// The first range that ends at or after id is the only one it could
+	// be in.
+	i, _ := slices.BinarySearchFunc(idx.merged, id, func(r Range, id uint64) int {
+		return cmp.Compare(r.End, id)
+	})
+	if i < len(idx.merged) && idx.merged[i].contains(id) {
+		return i
+	}
+	return -1
+}
+
+func (idx *index) contains(id uint64) bool {
+	return idx.find(id) >= 0
+}
+
+// covering returns the indexes of the original ranges that id is in, in
+// order.
+func (idx *index) covering(id uint64) []int {
+	i := idx.find(id)
+	if i < 0 {
+		return nil
+	}
+	var covering []int
+	for _, n := range idx.members[i] {
+		if idx.ranges[n].contains(id) {
+			covering = append(covering, n)
+		}
+	}
+	slices.Sort(covering)
+	return covering
 }
 
 type Range struct {
-	start, end uint64 // end is inclusive
+	Start, End uint64 // End is inclusive
 }
 
 func (r Range) contains(i uint64) bool {
-	return i >= r.start && i <= r.end
+	return i >= r.Start && i <= r.End
 }
 
 func (r Range) compare(s Range) int {
 	switch {
-	case r.start < s.start:
+	case r.Start < s.Start:
 		return -1
-	case r.start > s.start:
+	case r.Start > s.Start:
 		return 1
-	case r.end < s.end:
+	case r.End < s.End:
 		return -1
-	case r.end > s.end:
+	case r.End > s.End:
 		return 1
 	}
 	return 0
 }
 
-func read(r io.Reader) ([]Range, []uint64, error) {
-	var (
-		scan   = bufio.NewScanner(r)
-		ranges []Range
-		ids    []uint64
-	)
+// rangeDecoder is what parseRange, in parse_range.go, is generated from.
+//
+//go:generate go run ../aoc generate -decoder rangeDecoder -func parseRange
+var rangeDecoder = parse.MustDecoder[Range]("{start}-{end}")
+
+var generatedRangeDecoder = parse.DecoderOf(parseRange)
+
+// inputSyntax reads and prints a whole input: the ranges, a blank line, then
+// the IDs.
+var inputSyntax = parse.SeqSyntax(
+	parse.LinesSyntax(parse.FormatSyntax[Range]("{start}-{end}")),
+	parse.SeqRSyntax(parse.LiteralSyntax("\n"), parse.LinesSyntax(parse.UintSyntax[uint64]())),
+)
+
+// readRanges reads the first section of the input, up to the blank line.
+func readRanges(scan *bufio.Scanner) ([]Range, error) {
+	return readRangesWith(scan, generatedRangeDecoder)
+}
+
+// readRangesWith is readRanges with a particular decoder for each range.
+func readRangesWith(scan *bufio.Scanner, d *parse.Decoder[Range]) ([]Range, error) {
+	var ranges []Range
 	for scan.Scan() {
-		l := scan.Text()
-		if l == "" {
+		l := scan.Bytes()
+		if len(l) == 0 {
 			break
 		}
-		nums := strings.Split(l, "-")
-		if len(nums) != 2 {
-			return nil, nil, fmt.Errorf("unexpected input range: %q", l)
-		}
-		a, err := strconv.ParseUint(nums[0], 10, 64)
+		rng, err := d.Decode(l)
 		if err != nil {
-			return nil, nil, err
+			return nil, fmt.Errorf("unexpected input range %q: %w", l, err)
 		}
-		b, err := strconv.ParseUint(nums[1], 10, 64)
-		if err != nil {
-			return nil, nil, err
+		if rng.Start > rng.End {
+			return nil, fmt.Errorf("invalid range %d-%d", rng.Start, rng.End)
 		}
-		if a > b {
-			return nil, nil, fmt.Errorf("invalid range %d-%d", a, b)
-		}
-		ranges = append(ranges, Range{start: a, end: b})
+		ranges = append(ranges, rng)
 	}
 	if err := scan.Err(); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	for scan.Scan() {
-		n, err := strconv.ParseUint(scan.Text(), 10, 64)
-		if err != nil {
-			return nil, nil, err
+	return ranges, nil
+}
+
+// readIDs reads the rest of the input, one ID at a time. If something goes
+// wrong it yields the error and stops.
+func readIDs(scan *bufio.Scanner) iter.Seq2[uint64, error] {
+	return func(yield func(uint64, error) bool) {
+		for scan.Scan() {
+			n, err := strconv.ParseUint(scan.Text(), 10, 64)
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			if !yield(n, nil) {
+				return
+			}
+		}
+		if err := scan.Err(); err != nil {
+			yield(0, err)
 		}
-		ids = append(ids, n)
-	}
-	if err := scan.Err(); err != nil {
-		return nil, nil, err
 	}
-	return ranges, ids, nil
 }
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse"
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+const example = `3-5
+10-14
+16-20
+12-18
+
+1
+5
+8
+11
+17
+32
+`
+
+func TestExample(t *testing.T) {
+	scan := bufio.NewScanner(strings.NewReader(example))
+	ranges, err := readRanges(scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := newIndex(ranges)
+	got, err := partOne(idx, readIDs(scan))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("partOne(example) = %d, want 3", got)
+	}
+	if got := partTwo(idx); got != 14 {
+		t.Errorf("partTwo(example) = %d, want 14", got)
+	}
+
+	var sb strings.Builder
+	if err := query(&sb, idx, "1,5, 17"); err != nil {
+		t.Fatal(err)
+	}
+	want := `1: spoiled
+5: fresh, in 3-5 (range 1)
+17: fresh, in 16-20 (range 3), 12-18 (range 4)
+`
+	if sb.String() != want {
+		t.Errorf("query got\n%s\nwant\n%s", sb.String(), want)
+	}
+	if err := query(&sb, idx, "1,x"); err == nil {
+		t.Error("query(1,x): no error")
+	}
+}
+
+func TestReadIDsError(t *testing.T) {
+	scan := bufio.NewScanner(strings.NewReader("1-2\n\n1\nx\n3\n"))
+	ranges, err := readRanges(scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := partOne(newIndex(ranges), readIDs(scan)); err == nil {
+		t.Error("no error for x")
+	}
+}
+
+func TestIndex(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 5))
+	for range 100 {
+		ranges := make([]Range, 1+r.IntN(10))
+		for i := range ranges {
+			start := r.Uint64N(100)
+			ranges[i] = Range{start, start + r.Uint64N(10)}
+		}
+		idx := newIndex(ranges)
+		for id := range uint64(120) {
+			var want []int
+			for i, rng := range ranges {
+				if rng.contains(id) {
+					want = append(want, i)
+				}
+			}
+			if got := idx.covering(id); !slices.Equal(got, want) {
+				t.Fatalf("%v: covering(%d) = %v, want %v", ranges, id, got, want)
+			}
+			if got := idx.contains(id); got != (len(want) > 0) {
+				t.Fatalf("%v: contains(%d) = %v, want %v", ranges, id, got, len(want) > 0)
+			}
+		}
+		for i := 1; i < len(idx.merged); i++ {
+			if idx.merged[i-1].End >= idx.merged[i].Start {
+				t.Fatalf("%v: merged ranges %v overlap", ranges, idx.merged)
+			}
+		}
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	parsetest.Generated(t, rangeDecoder, "main", "parseRange", "parse_range.go")
+}
+
+// TestReaders checks that the generated parser reads the same ranges as the
+// decoder it came from, and fails in the same way on broken inputs.
+func TestReaders(t *testing.T) {
+	readWith := func(d *parse.Decoder[Range]) func([]byte) ([]Range, error) {
+		return func(in []byte) ([]Range, error) {
+			return readRangesWith(bufio.NewScanner(bytes.NewReader(in)), d)
+		}
+	}
+	parsetest.Readers[[]Range]{
+		Got:    readWith(generatedRangeDecoder),
+		Want:   readWith(rangeDecoder),
+		Breaks: []byte{'x', '\n', '-', '9', ' '},
+		At: func(input []byte) []int {
+			// Only the ranges, before the blank line.
+			end := bytes.Index(input, []byte("\n\n"))
+			return []int{0, 1, end / 3, end / 2, end - 1, end}
+		},
+		Extra: func(input []byte) [][]byte {
+			return [][]byte{append([]byte("1-99999999999999999999\n"), input...)}
+		},
+	}.Test(t, "inputs/example.txt", "inputs/input.txt")
+}
+
+func TestInputSyntax(t *testing.T) {
+	read := func(in []byte) (parse.Pair[[]Range, []uint64], error) {
+		scan := bufio.NewScanner(bytes.NewReader(in))
+		ranges, err := readRanges(scan)
+		if err != nil {
+			return parse.Pair[[]Range, []uint64]{}, err
+		}
+		var ids []uint64
+		for id, err := range readIDs(scan) {
+			if err != nil {
+				return parse.Pair[[]Range, []uint64]{}, err
+			}
+			ids = append(ids, id)
+		}
+		return parse.Pair[[]Range, []uint64]{First: ranges, Second: ids}, nil
+	}
+	parsetest.RoundTrip(t, inputSyntax, read, "inputs/example.txt", "inputs/input.txt")
+}
+
+func BenchmarkReadRanges(b *testing.B) {
+	input, err := os.ReadFile("inputs/input.txt")
+	if err != nil {
+		b.Skip(err)
+	}
+	for _, c := range []struct {
+		name string
+		d    *parse.Decoder[Range]
+	}{
+		{"decoder", rangeDecoder},
+		{"generated", generatedRangeDecoder},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := readRangesWith(bufio.NewScanner(bytes.NewReader(input)), c.d); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Code generated by parse.Decoder.Generate. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// parseRange decodes Range values with the format "{start}-{end}".
+func parseRange(_ *parse.State, input []byte) (parse.ParseResult[Range], error) {
+	return parse.DecodeWith(input, parseRange_1)
+}
+
+// Range with the format "{start}-{end}"
+func parseRange_1(input []byte, v *Range) ([]byte, error) {
+	var err error
+	if input, err = parse.DecodeUint(input, &v.Start, "uint64"); err != nil {
+		return nil, parse.NewFieldError("start", err)
+	}
+	if input, err = parse.DecodeLiteral(input, "-"); err != nil {
+		return nil, err
+	}
+	if input, err = parse.DecodeUint(input, &v.End, "uint64"); err != nil {
+		return nil, parse.NewFieldError("end", err)
+	}
+	return input, nil
+}
@@ -11,20 +11,31 @@ import (
 	"runtime/pprof"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/automaton"
+	"github.com/pfcm/aoc25/parse"
 )
 
 var (
 	profileFlag = flag.String("profile", "", "`path` to write profiles for part two")
+	implFlag    = flag.String("impl", "bits", "how to do part two: rescan, queue, automaton or bits")
 )
 
 func main() {
 	flag.Parse()
+	removals, ok := implementations[*implFlag]
+	if !ok {
+		log.Fatalf("unknown -impl %q", *implFlag)
+	}
 
 	cells, err := read(os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
-	aoc25.PrintTiming("Part one", func() int { return partOne(cells) })
+	grid, err := automaton.BitsFromBools(cells)
+	if err != nil {
+		log.Fatal(err)
+	}
+	aoc25.PrintTiming("Part one", func() int { return partOne(grid) })
 
 	if *profileFlag != "" {
 		finish, err := startProfiles(*profileFlag)
@@ -37,7 +48,13 @@ func main() {
 			}
 		}()
 	}
-	aoc25.PrintTiming("Part two", func() int { return partTwo(cells) })
+	aoc25.PrintTiming("Part two", func() int {
+		n, err := partTwo(cells, removals)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return n
+	})
 }
 
 func startProfiles(path string) (func() error, error) {
@@ -56,7 +73,42 @@ func startProfiles(path string) (func() error, error) {
 	}, nil
 }
 
-func partTwo(cells [][]bool) int {
+// implementations are the ways to work out when each roll gets removed in
+// part two. They should all give the same answer.
+var implementations = map[string]func([][]bool) ([][]int, error){
+	"rescan":    infallible(rescan),
+	"queue":     infallible(workQueue),
+	"automaton": runAutomaton,
+	"bits":      bitRounds,
+}
+
+// infallible is for the implementations that work on any grid.
+func infallible(f func([][]bool) [][]int) func([][]bool) ([][]int, error) {
+	return func(cells [][]bool) ([][]int, error) { return f(cells), nil }
+}
+
+// partTwo counts how many rolls can be removed, removing every accessible
+// roll at once and repeating until there aren't any.
+func partTwo(cells [][]bool, removals func([][]bool) ([][]int, error)) (int, error) {
+	rounds, err := removals(cells)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, row := range rounds {
+		for _, round := range row {
+			if round != 0 {
+				total++
+			}
+		}
+	}
+	return total, nil
+}
+
+// rescan returns the round each roll was removed in, counting from one, or 0
+// if it never was. It looks at every cell each round until a round doesn't
+// remove anything.
+func rescan(cells [][]bool) [][]int {
 	cpy := func(dest, src [][]bool) {
 		if len(dest) != len(src) {
 			panic("they are different")
@@ -65,33 +117,167 @@ func partTwo(cells [][]bool) int {
 			copy(dest[i], src[i])
 		}
 	}
-	next := make([][]bool, len(cells))
+	var (
+		current = make([][]bool, len(cells))
+		next    = make([][]bool, len(cells))
+		rounds  = make([][]int, len(cells))
+	)
 	for i := range cells {
+		current[i] = make([]bool, len(cells[i]))
 		next[i] = make([]bool, len(cells[i]))
+		rounds[i] = make([]int, len(cells[i]))
 	}
+	cpy(current, cells)
 	cpy(next, cells)
-	var (
-		total int
-		round = 1
-	)
-	for round != 0 {
-		round = 0
+	for round, removed := 1, 1; removed != 0; round++ {
+		removed = 0
 
-		for r, row := range cells {
+		for r, row := range current {
 			for c, cell := range row {
-				if cell && accessible(cells, r, c) {
-					round++
+				if cell && accessible(current, r, c) {
+					removed++
 					next[r][c] = false
+					rounds[r][c] = round
 				}
 			}
 		}
-		cpy(cells, next)
-		total += round
+		cpy(current, next)
 	}
-	return total
+	return rounds
+}
+
+// workQueue is rescan, but it keeps a count of each roll's neighbours and
+// only looks again at the rolls next to ones that were just removed, because
+// nothing else can have become accessible. Each round's queue is the rolls
+// that will be removed in it.
+func workQueue(cells [][]bool) [][]int {
+	var (
+		counts = make([][]int, len(cells))
+		rounds = make([][]int, len(cells))
+		queue  [][2]int
+	)
+	for r, row := range cells {
+		counts[r] = make([]int, len(row))
+		rounds[r] = make([]int, len(row))
+		for c, cell := range row {
+			if !cell {
+				continue
+			}
+			for n := range neighbourhood(cells, r, c) {
+				if n {
+					counts[r][c]++
+				}
+			}
+			if counts[r][c] < 4 {
+				rounds[r][c] = 1
+				queue = append(queue, [2]int{r, c})
+			}
+		}
+	}
+	var next [][2]int
+	for round := 1; len(queue) > 0; round++ {
+		for _, rc := range queue {
+			for _, n := range neighbours(cells, rc[0], rc[1]) {
+				r, c := n[0], n[1]
+				if !cells[r][c] {
+					continue
+				}
+				counts[r][c]--
+				// Only the removal that takes it down to three
+				// queues it, so nothing gets queued twice.
+				if counts[r][c] == 3 && rounds[r][c] == 0 {
+					rounds[r][c] = round + 1
+					next = append(next, n)
+				}
+			}
+		}
+		queue, next = next, queue[:0]
+	}
+	return rounds
+}
+
+// removal is day four's rule as an automaton: rolls with fewer than four of
+// their eight neighbours occupied get removed, and nothing comes back.
+var removal = automaton.Automaton{
+	Neighbourhood: automaton.Moore(1),
+	Rule:          automaton.Threshold(automaton.Count{Min: 4, Max: 8}, automaton.None),
 }
 
-func partOne(cells [][]bool) int {
+// runAutomaton is rescan using the automaton package, stepping until nothing
+// changes. Unlike rescan, the rows all have to be the same length.
+func runAutomaton(cells [][]bool) ([][]int, error) {
+	g, err := automaton.FromBools(cells)
+	if err != nil {
+		return nil, err
+	}
+	rounds := make([][]int, len(cells))
+	for r := range rounds {
+		rounds[r] = make([]int, len(cells[r]))
+	}
+	for round := 1; ; round++ {
+		next, changed, err := removal.Step(g)
+		if err != nil {
+			return nil, err
+		}
+		if changed == 0 {
+			return rounds, nil
+		}
+		for r := range g.Rows() {
+			for c := range g.Cols() {
+				if g.Get(r, c) && !next.Get(r, c) {
+					rounds[r][c] = round
+				}
+			}
+		}
+		g = next
+	}
+}
+
+// bitRounds is runAutomaton on a bit-packed grid, which counts the
+// neighbours of a whole word of cells at once.
+func bitRounds(cells [][]bool) ([][]int, error) {
+	g, err := automaton.BitsFromBools(cells)
+	if err != nil {
+		return nil, err
+	}
+	rounds := make([][]int, len(cells))
+	for r := range rounds {
+		rounds[r] = make([]int, len(cells[r]))
+	}
+	for round := 1; ; round++ {
+		next, changed := g.Step(removal.Rule)
+		if changed == 0 {
+			return rounds, nil
+		}
+		g.Diff(next, func(r, c int) { rounds[r][c] = round })
+		g = next
+	}
+}
+
+// neighbours returns the positions around a cell that are on the grid.
+func neighbours(cells [][]bool, row, col int) [][2]int {
+	var ns [][2]int
+	for _, dr := range []int{-1, 0, 1} {
+		for _, dc := range []int{-1, 0, 1} {
+			r, c := row+dr, col+dc
+			if (dr == 0 && dc == 0) || r < 0 || c < 0 || r >= len(cells) || c >= len(cells[r]) {
+				continue
+			}
+			ns = append(ns, [2]int{r, c})
+		}
+	}
+	return ns
+}
+
+// partOne counts the accessible rolls, which are the ones the first round
+// of removals takes.
+func partOne(g *automaton.BitGrid) int {
+	_, removed := g.Step(removal.Rule)
+	return removed
+}
+
+// scanOne is partOne looking at every cell's neighbours one at a time.
+func scanOne(cells [][]bool) int {
 	total := 0
 	for r, row := range cells {
 		for c, cell := range row {
@@ -119,7 +305,7 @@ func neighbourhood(cells [][]bool, row, col int) iter.Seq[bool] {
 			if i < 0 || j < 0 {
 				return false
 			}
-			if i >= len(cells) || j >= len(cells[0]) {
+			if i >= len(cells) || j >= len(cells[i]) {
 				return false
 			}
 			return cells[i][j]
@@ -137,6 +323,11 @@ func neighbourhood(cells [][]bool, row, col int) iter.Seq[bool] {
 	}
 }
 
+// inputSyntax reads and prints a whole input, with a roll of paper as @ and
+// nothing as a dot. It's stricter than read, which takes anything but @ to
+// be empty.
+var inputSyntax = parse.LinesSyntax(parse.ManySyntax(parse.EnumSyntax(map[string]bool{".": false, "@": true})))
+
 func read(r io.Reader) ([][]bool, error) {
 	var (
 		scan  = bufio.NewScanner(r)
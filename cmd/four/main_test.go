@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pfcm/aoc25/automaton"
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+const example = `..@@.@@@@.
+@@@.@.@.@@
+@@@@@.@.@@
+@.@@@@..@.
+@@.@@@@.@@
+.@@@@@@@.@
+.@.@.@.@@@
+@.@@@.@@@@
+.@@@@@@@@.
+@.@.@@@.@.
+`
+
+func readString(t testing.TB, s string) [][]bool {
+	t.Helper()
+	cells, err := read(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cells
+}
+
+func TestExample(t *testing.T) {
+	cells := readString(t, example)
+	if got := scanOne(cells); got != 13 {
+		t.Errorf("scanOne(example) = %d, want 13", got)
+	}
+	if got := partOne(bits(t, cells)); got != 13 {
+		t.Errorf("partOne(example) = %d, want 13", got)
+	}
+	for name, removals := range implementations {
+		if got, err := partTwo(cells, removals); err != nil || got != 43 {
+			t.Errorf("partTwo(example, %s) = %d, %v, want 43", name, got, err)
+		}
+	}
+	// The first round is what part one finds.
+	rounds := workQueue(cells)
+	first := 0
+	for _, row := range rounds {
+		for _, round := range row {
+			if round == 1 {
+				first++
+			}
+		}
+	}
+	if first != 13 {
+		t.Errorf("workQueue(example) removes %d in the first round, want 13", first)
+	}
+}
+
+func bits(t testing.TB, cells [][]bool) *automaton.BitGrid {
+	t.Helper()
+	g, err := automaton.BitsFromBools(cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// checkSame checks that every implementation removes the same rolls in the
+// same rounds, and leaves the grid alone.
+func checkSame(t *testing.T, cells [][]bool) {
+	t.Helper()
+	if got, want := partOne(bits(t, cells)), scanOne(cells); got != want {
+		t.Errorf("partOne = %d, scanOne gives %d", got, want)
+	}
+	want := rescan(cells)
+	for name, removals := range implementations {
+		var before [][]bool
+		for _, row := range cells {
+			before = append(before, append([]bool(nil), row...))
+		}
+		if got, err := removals(cells); err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s gives different rounds to rescan", name)
+		}
+		if !reflect.DeepEqual(cells, before) {
+			t.Errorf("%s changed the grid", name)
+		}
+	}
+}
+
+func TestInputSyntax(t *testing.T) {
+	parsetest.RoundTrip(t, inputSyntax, func(in []byte) ([][]bool, error) { return read(bytes.NewReader(in)) },
+		"input/example.txt", "input/input.txt")
+}
+
+func TestImplementations(t *testing.T) {
+	r := rand.New(rand.NewPCG(4, 4))
+	for range 100 {
+		cells := make([][]bool, 1+r.IntN(20))
+		width := 1 + r.IntN(20)
+		for i := range cells {
+			cells[i] = make([]bool, width)
+			for j := range cells[i] {
+				cells[i][j] = r.IntN(3) != 0
+			}
+		}
+		checkSame(t, cells)
+	}
+
+	input, err := os.ReadFile("input/input.txt")
+	if err != nil {
+		t.Skip(err)
+	}
+	checkSame(t, readString(t, string(input)))
+}
+
+func TestRagged(t *testing.T) {
+	for _, c := range []struct {
+		cells [][]bool
+		want  [][]int
+	}{
+		{[][]bool{{true, true}, {true}}, [][]int{{1, 1}, {1}}},
+		{[][]bool{{true}, {true, true}}, [][]int{{1}, {1, 1}}},
+		// Where they only have each other to check against.
+		{[][]bool{{true, true, true}, {true, true, true, true, true}, {true, true}}, nil},
+	} {
+		cells, want := c.cells, c.want
+		for name, removals := range implementations {
+			rounds, err := removals(cells)
+			switch name {
+			case "automaton", "bits":
+				// These need a rectangle.
+				if err == nil {
+					t.Errorf("%s(%v): no error for rows of different lengths", name, cells)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s(%v): %v", name, cells, err)
+				continue
+			}
+			if want == nil {
+				want = rounds
+			} else if !reflect.DeepEqual(rounds, want) {
+				t.Errorf("%s(%v) = %v, others give %v", name, cells, rounds, want)
+			}
+		}
+	}
+}
+
+func BenchmarkPartOne(b *testing.B) {
+	input, err := os.ReadFile("input/input.txt")
+	if err != nil {
+		b.Skip(err)
+	}
+	cells := readString(b, string(input))
+	b.Run("scan", func(b *testing.B) {
+		for b.Loop() {
+			scanOne(cells)
+		}
+	})
+	b.Run("bits", func(b *testing.B) {
+		g := bits(b, cells)
+		for b.Loop() {
+			partOne(g)
+		}
+	})
+}
+
+func BenchmarkPartTwo(b *testing.B) {
+	input, err := os.ReadFile("input/input.txt")
+	if err != nil {
+		b.Skip(err)
+	}
+	cells := readString(b, string(input))
+	for name, removals := range implementations {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				partTwo(cells, removals)
+			}
+		})
+	}
+}
@@ -2,16 +2,14 @@
 package main
 
 import (
-	"bufio"
-	"fmt"
+	"errors"
 	"io"
 	"iter"
 	"log"
 	"os"
-	"strconv"
-	"strings"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
 func main() {
@@ -47,16 +45,16 @@ func partOne(points []point) int64 {
 
 func partTwo(points []point) int64 {
 	contained := func(p, q point) (result bool) {
-		minX, minY := min(p.x, q.x), min(p.y, q.y)
-		maxX, maxY := max(p.x, q.x), max(p.y, q.y)
+		minX, minY := min(p.X, q.X), min(p.Y, q.Y)
+		maxX, maxY := max(p.X, q.X), max(p.Y, q.Y)
 		// If there are any points in the shape that are inside the
 		// rectangle that are not on the edge, then the rectangle _must_
 		// go outside the shape.
 		for _, p := range points {
-			if p.x <= minX || p.x >= maxX {
+			if p.X <= minX || p.X >= maxX {
 				continue
 			}
-			if p.y <= minY || p.y >= maxY {
+			if p.Y <= minY || p.Y >= maxY {
 				continue
 			}
 			return false
@@ -67,24 +65,24 @@ func partTwo(points []point) int64 {
 		for i := range points {
 			start, end := points[i], points[(i+1)%len(points)]
 			// TODO: these conditions seem unreasonably complicated
-			if start.x == end.x {
+			if start.X == end.X {
 				// vertical line
-				if start.x <= minX || start.x >= maxX {
+				if start.X <= minX || start.X >= maxX {
 					continue
 				}
-				start.y, end.y = min(start.y, end.y), max(start.y, end.y)
-				if start.y <= minY && end.y >= maxY {
+				start.Y, end.Y = min(start.Y, end.Y), max(start.Y, end.Y)
+				if start.Y <= minY && end.Y >= maxY {
 					// crosses the rectangle
 					return false
 				}
 				continue
-			} else if start.y == end.y {
+			} else if start.Y == end.Y {
 				// horizontal line
-				if start.y <= minY || start.y >= maxY {
+				if start.Y <= minY || start.Y >= maxY {
 					continue
 				}
-				start.x, end.x = min(start.x, end.x), max(start.x, end.x)
-				if start.x <= minX && end.x >= maxX {
+				start.X, end.X = min(start.X, end.X), max(start.X, end.X)
+				if start.X <= minX && end.X >= maxX {
 					return false
 				}
 				continue
@@ -118,7 +116,7 @@ func iterLine(start, end point) iter.Seq[point] {
 		}
 		return 0
 	}
-	d := point{sgn(end.x - start.x), sgn(end.y - start.y)}
+	d := point{sgn(end.X - start.X), sgn(end.Y - start.Y)}
 
 	return func(yield func(point) bool) {
 		for x := start; x != end; x = x.add(d) {
@@ -132,41 +130,51 @@ func iterLine(start, end point) iter.Seq[point] {
 
 func area(a, b point) int64 {
 	minPoint := point{
-		x: min(a.x, b.x),
-		y: min(a.y, b.y),
+		X: min(a.X, b.X),
+		Y: min(a.Y, b.Y),
 	}
 	maxPoint := point{
-		x: max(a.x, b.x),
-		y: max(a.y, b.y),
+		X: max(a.X, b.X),
+		Y: max(a.Y, b.Y),
 	}
-	return (maxPoint.x - minPoint.x + 1) * (maxPoint.y - minPoint.y + 1)
+	return (maxPoint.X - minPoint.X + 1) * (maxPoint.Y - minPoint.Y + 1)
 }
 
 type point struct {
-	x, y int64
+	X, Y int64
 }
 
-func (p point) add(q point) point { return point{p.x + q.x, p.y + q.y} }
+func (p point) add(q point) point { return point{p.X + q.X, p.Y + q.Y} }
 
+// pointDecoder is what parsePoint, in parse_point.go, is generated from.
+//
+//go:generate go run ../aoc generate -decoder pointDecoder -func parsePoint
+var pointDecoder = parse.MustDecoder[point]("{x},{y}")
+
+// inputSyntax reads and prints a whole input, a point to a line.
+var inputSyntax = parse.LinesSyntax(parse.FormatSyntax[point]("{x},{y}"))
+
+// read reads all of the points, reporting every line that's wrong rather than
+// just the first.
 func read(r io.Reader) ([]point, error) {
+	return readWith(r, parsePoint)
+}
+
+// readWith is read with a particular parser for each line.
+func readWith(r io.Reader, parsePoint parse.Parser[point]) ([]point, error) {
 	var (
 		results []point
-		scan    = bufio.NewScanner(r)
+		errs    []error
 	)
-	for scan.Scan() {
-		nums := strings.Split(scan.Text(), ",")
-		if l := len(nums); l != 2 {
-			return nil, fmt.Errorf("invalid line: %q", l)
-		}
-		x, err := strconv.ParseInt(nums[0], 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		y, err := strconv.ParseInt(nums[1], 10, 64)
+	for p, err := range parse.Lines(r, parsePoint) {
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			continue
 		}
-		results = append(results, point{x: x, y: y})
+		results = append(results, p)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
 	}
 	return results, nil
 }
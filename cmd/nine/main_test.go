@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+func TestGenerated(t *testing.T) {
+	parsetest.Generated(t, pointDecoder, "main", "parsePoint", "parse_point.go")
+}
+
+// TestReaders checks that the generated parser reads the same points as the
+// decoder it came from, and fails in the same way on broken inputs.
+func TestReaders(t *testing.T) {
+	parsetest.Readers[[]point]{
+		Got:    func(in []byte) ([]point, error) { return read(bytes.NewReader(in)) },
+		Want:   func(in []byte) ([]point, error) { return readWith(bytes.NewReader(in), pointDecoder.Parser()) },
+		Breaks: []byte{'x', '\n', ',', '9', '-', ' '},
+		Extra: func(input []byte) [][]byte {
+			return [][]byte{append(input[:len(input)-1:len(input)-1], "99999999999999999999"...)}
+		},
+	}.Test(t, "inputs/example.txt", "inputs/inputs.txt")
+}
+
+func TestInputSyntax(t *testing.T) {
+	parsetest.RoundTrip(t, inputSyntax, func(in []byte) ([]point, error) { return read(bytes.NewReader(in)) },
+		"inputs/example.txt", "inputs/inputs.txt")
+}
+
+func BenchmarkRead(b *testing.B) {
+	input, err := os.ReadFile("inputs/inputs.txt")
+	if err != nil {
+		b.Skip(err)
+	}
+	for _, c := range []struct {
+		name string
+		read func([]byte) ([]point, error)
+	}{
+		{"decoder", func(in []byte) ([]point, error) { return readWith(bytes.NewReader(in), pointDecoder.Parser()) }},
+		{"generated", func(in []byte) ([]point, error) { return read(bytes.NewReader(in)) }},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for b.Loop() {
+				if _, err := c.read(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
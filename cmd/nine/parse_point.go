@@ -0,0 +1,27 @@
+// Code generated by parse.Decoder.Generate. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// parsePoint decodes point values with the format "{x},{y}".
+func parsePoint(_ *parse.State, input []byte) (parse.ParseResult[point], error) {
+	return parse.DecodeWith(input, parsePoint_1)
+}
+
+// point with the format "{x},{y}"
+func parsePoint_1(input []byte, v *point) ([]byte, error) {
+	var err error
+	if input, err = parse.DecodeInt(input, &v.X, "int64"); err != nil {
+		return nil, parse.NewFieldError("x", err)
+	}
+	if input, err = parse.DecodeLiteral(input, ","); err != nil {
+		return nil, err
+	}
+	if input, err = parse.DecodeInt(input, &v.Y, "int64"); err != nil {
+		return nil, parse.NewFieldError("y", err)
+	}
+	return input, nil
+}
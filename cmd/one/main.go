@@ -3,75 +3,121 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"strconv"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
+)
+
+var (
+	sizeFlag  = flag.Int("size", 100, "number of positions on the dial")
+	startFlag = flag.Int("start", 50, "position the dial starts at")
 )
 
 func main() {
+	flag.Parse()
+	d := dial{size: *sizeFlag, start: *startFlag}
+	if d.size <= 0 || d.start < 0 || d.start >= d.size {
+		log.Fatalf("can't start at %d on a dial of size %d", d.start, d.size)
+	}
+
 	input, err := read(os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
-	aoc25.PrintTiming("Part one", func() int { return partOne(input) })
-	aoc25.PrintTiming("Part two", func() int { return partTwo(input) })
+	aoc25.PrintTiming("Part one", func() int { return partOne(input, d) })
+	aoc25.PrintTiming("Part two", func() int { return partTwo(input, d) })
 }
 
-func partTwo(turns []int) int {
+// dial is a dial with positions 0 to size-1, that starts pointing at start.
+type dial struct {
+	size, start int
+}
+
+// partOne counts the turns that end pointing at zero.
+func partOne(turns []int, d dial) int {
 	var (
-		pos   = 50
+		pos   = d.start
 		zeros = 0
 	)
 	for _, t := range turns {
-		// lol
-		s := 1
-		if t < 0 {
-			s = -1
-		}
-		if t < 0 {
-			t = -t
-		}
-		for range t {
-			pos += s
-			if pos == 0 {
-				zeros++
-			}
-			switch pos {
-			case -1:
-				pos = 99
-			case 100:
-				pos = 0
-				zeros++
-			}
+		pos = floorMod(pos+t, d.size)
+		if pos == 0 {
+			zeros++
 		}
 	}
 	return zeros
 }
 
-func partOne(turns []int) int {
-	clampMod := func(i int) int {
-		for i < 0 {
-			i += 100
-		}
-		return i % 100
-	}
+// partTwo counts every click that leaves the dial pointing at zero, including
+// those in the middle of turns.
+func partTwo(turns []int, d dial) int {
 	var (
-		pos   = 50
+		pos   = d.start
 		zeros = 0
 	)
 	for _, t := range turns {
-		pos = clampMod(pos + t)
-		if pos == 0 {
-			zeros++
+		// The clicks go through every position in (pos, pos+t] turning
+		// right, or [pos+t, pos) turning left, and the zeros are the
+		// multiples of the size in there.
+		if t > 0 {
+			zeros += floorDiv(pos+t, d.size) - floorDiv(pos, d.size)
+		} else {
+			zeros += floorDiv(pos-1, d.size) - floorDiv(pos+t-1, d.size)
 		}
+		pos = floorMod(pos+t, d.size)
 	}
 	return zeros
 }
 
+// floorDiv is a/b rounded down, rather than towards zero.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// floorMod is the remainder to go with floorDiv, which has the same sign as
+// b.
+func floorMod(a, b int) int {
+	return a - floorDiv(a, b)*b
+}
+
+// turn parses a line like L68 into a number of clicks: right rotations are
+// positive, left negative. It reads the line as a string, so that lines from
+// a scanner don't need converting.
+var turn = parse.SeqL(
+	parse.Apply(
+		parse.Seq(parse.Or(parse.ByteOf[string]('L'), parse.ByteOf[string]('R')), parse.IntOf[int, string]),
+		func(p parse.Pair[byte, int]) int {
+			if p.First == 'L' {
+				return -p.Second
+			}
+			return p.Second
+		},
+	),
+	parse.EndOf[string],
+)
+
+// inputSyntax reads and prints a whole input, the same way as turn and read.
+// A turn of no clicks prints as R0.
+var inputSyntax = parse.LinesSyntax(parse.MapSyntax(
+	parse.SeqSyntax(parse.EnumSyntax(map[string]int{"L": -1, "R": 1}), parse.UintSyntax[uint]()),
+	func(p parse.Pair[int, uint]) int { return p.First * int(p.Second) },
+	func(clicks int) parse.Pair[int, uint] {
+		if clicks < 0 {
+			return parse.Pair[int, uint]{First: -1, Second: uint(-clicks)}
+		}
+		return parse.Pair[int, uint]{First: 1, Second: uint(clicks)}
+	},
+))
+
 // read reads the input from the provided reader, as a list of integers: right
 // rotations are positive, left negative.
 func read(r io.Reader) ([]int, error) {
@@ -81,17 +127,9 @@ func read(r io.Reader) ([]int, error) {
 	)
 	for scan.Scan() {
 		line := scan.Text()
-		num, err := strconv.Atoi(line[1:])
+		num, err := parse.Run(turn, line)
 		if err != nil {
-			return nil, err
-		}
-		switch line[0] {
-		case 'L':
-			num = -num
-		case 'R':
-			// all good
-		default:
-			return nil, fmt.Errorf("bad line: %q", line)
+			return nil, fmt.Errorf("bad line %q: %w", line, err)
 		}
 		results = append(results, num)
 	}
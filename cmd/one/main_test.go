@@ -1,10 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"encoding/binary"
 	"os"
 	"testing"
+
+	"github.com/pfcm/aoc25/parse/parsetest"
 )
 
+var puzzleDial = dial{size: 100, start: 50}
+
 func BenchmarkParts(b *testing.B) {
 	f, err := os.Open("./inputs/input.txt")
 	if err != nil {
@@ -16,19 +22,122 @@ func BenchmarkParts(b *testing.B) {
 	}
 	for _, c := range []struct {
 		name string
-		f    func([]int) int
+		f    func([]int, dial) int
 	}{{
 		name: "one",
 		f:    partOne,
 	}, {
 		name: "two",
 		f:    partTwo,
+	}, {
+		name: "one-steps",
+		f:    stepsOne,
+	}, {
+		name: "two-steps",
+		f:    stepsTwo,
 	}} {
 		b.Run(c.name, func(b *testing.B) {
 			for b.Loop() {
-				x := c.f(input)
+				x := c.f(input, puzzleDial)
 				_ = x
 			}
 		})
 	}
 }
+
+// stepsOne is the original part one, which normalises negative positions
+// with a loop.
+func stepsOne(turns []int, d dial) int {
+	clampMod := func(i int) int {
+		for i < 0 {
+			i += d.size
+		}
+		return i % d.size
+	}
+	var (
+		pos   = d.start
+		zeros = 0
+	)
+	for _, t := range turns {
+		pos = clampMod(pos + t)
+		if pos == 0 {
+			zeros++
+		}
+	}
+	return zeros
+}
+
+// stepsTwo is the original part two, which turns the dial one click at a
+// time.
+func stepsTwo(turns []int, d dial) int {
+	var (
+		pos   = d.start
+		zeros = 0
+	)
+	for _, t := range turns {
+		s := 1
+		if t < 0 {
+			s = -1
+		}
+		if t < 0 {
+			t = -t
+		}
+		for range t {
+			pos += s
+			if pos == 0 {
+				zeros++
+			}
+			switch pos {
+			case -1:
+				pos = d.size - 1
+			case d.size:
+				pos = 0
+				zeros++
+			}
+		}
+	}
+	return zeros
+}
+
+func TestParts(t *testing.T) {
+	turns := []int{-68, -30, 48, -5, 60, -55, -1, -99, 14, -82}
+	if got := partOne(turns, puzzleDial); got != 3 {
+		t.Errorf("partOne(example) = %d, want 3", got)
+	}
+	if got := partTwo(turns, puzzleDial); got != 6 {
+		t.Errorf("partTwo(example) = %d, want 6", got)
+	}
+	// A billion clicks is ten million times round.
+	if got := partTwo([]int{1_000_000_000}, puzzleDial); got != 10_000_000 {
+		t.Errorf("partTwo(R1000000000) = %d, want 10000000", got)
+	}
+}
+
+func FuzzParts(f *testing.F) {
+	f.Add(uint8(98), uint8(50), []byte{0x00, 0x44, 0xff, 0xe2, 0x00, 0x30})
+	f.Add(uint8(0), uint8(0), []byte{0x03, 0xe8, 0xfc, 0x18})
+	f.Add(uint8(7), uint8(6), []byte{0x00, 0x00, 0x00, 0x07, 0xff, 0xf9})
+	f.Fuzz(func(t *testing.T, size, start uint8, raw []byte) {
+		// The step by step part two can't count zeros when a dial of
+		// size one goes left, so start at two.
+		d := dial{size: int(size)%200 + 2}
+		d.start = int(start) % d.size
+		var turns []int
+		for i := 0; i+1 < len(raw); i += 2 {
+			// Keep the turns small enough for the step by step
+			// versions to be quick.
+			turns = append(turns, int(int16(binary.BigEndian.Uint16(raw[i:])))%2000)
+		}
+		if got, want := partOne(turns, d), stepsOne(turns, d); got != want {
+			t.Errorf("partOne(%v, %+v) = %d, steps give %d", turns, d, got, want)
+		}
+		if got, want := partTwo(turns, d), stepsTwo(turns, d); got != want {
+			t.Errorf("partTwo(%v, %+v) = %d, steps give %d", turns, d, got, want)
+		}
+	})
+}
+
+func TestInputSyntax(t *testing.T) {
+	parsetest.RoundTrip(t, inputSyntax, func(in []byte) ([]int, error) { return read(bytes.NewReader(in)) },
+		"inputs/example.txt", "inputs/input.txt")
+}
@@ -3,132 +3,222 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"math"
+	"math/big"
+	"math/bits"
 	"os"
-	"strings"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
+var exitsFlag = flag.Bool("exits", false, "print how many timelines leave from each column")
+
 func main() {
+	flag.Parse()
 	grid, err := read(os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	aoc25.PrintTiming("Part one", func() int { return partOne(grid) })
-	aoc25.PrintTiming("Part two", func() int { return partTwo(grid) })
+	var t timelines
+	aoc25.PrintTiming("Part two", func() *big.Int {
+		t = partTwo(grid)
+		return t.total()
+	})
+	if *exitsFlag {
+		t.printExits(os.Stdout)
+	}
 }
 
+// partOne counts how many splitters a beam reaches.
 func partOne(grid [][]Cell) int {
-	grid = copyGrid(grid)
-
-	start := -1
-	for i, c := range grid[0] {
-		if c == Start {
-			start = i
-			break
-		}
-	}
-	if start == -1 {
-		panic("no start position")
-	}
-
-	grid[0][start] = Ray
-
-	// printGrid(grid)
-
-	splits := 0
-	for i, row := range grid[:len(grid)-1] {
-		for j, c := range row {
-			switch c {
-			case Ray:
-				switch c2 := grid[i+1][j]; c2 {
-				case Start:
-					panic("oh my")
-				case Ray:
-					// this is fine, a splitter probably did
-					// the thing through a splitter.
-				case Empty:
-					grid[i+1][j] = Ray
-				case Splitter:
-					if j == 0 || j >= len(grid[i+1]) {
-						panic("splitter too close to the edge")
-					}
-					splits++
-					// Check what's there before overwriting?
-					grid[i+1][j-1] = Ray
-					grid[i+1][j+1] = Ray
-				default:
-					panic(fmt.Errorf("what is %v", c2))
+	var (
+		beams  = make([]bool, width(grid)) // going down into the row
+		splits = 0
+	)
+	for _, row := range grid {
+		next := make([]bool, len(beams))
+		for j := range beams {
+			switch c := cell(row, j); {
+			case c == Start:
+				next[j] = true
+			case !beams[j]:
+			case c == Splitter:
+				splits++
+				if j > 0 {
+					next[j-1] = true
+				}
+				if j+1 < len(next) {
+					next[j+1] = true
 				}
-			case Start:
-				panic("unpossible")
-			case Splitter:
-			case Empty:
 			default:
+				next[j] = true
 			}
 		}
-		// printGrid(grid)
+		beams = next
 	}
 	return splits
 }
 
-func partTwo(grid [][]Cell) int {
-	// nb. just enumerating all the paths with a graph search was indeed too
-	// slow. But just doing the same thing and memoising might be cool.
-	memoed := make([][]int, len(grid))
-	for i := range memoed {
-		memoed[i] = make([]int, len(grid[i]))
-		for j := range memoed[i] {
-			memoed[i][j] = -1
-		}
+// timelines is where all the timelines leave the grid.
+type timelines struct {
+	// exits has how many leave off the left side first, then how many
+	// leave from the bottom of each column, then how many leave off the
+	// right side, from splitters on the edges.
+	exits *counts
+}
+
+func (t timelines) total() *big.Int {
+	sum := new(big.Int)
+	for i := range t.exits.len() {
+		sum.Add(sum, t.exits.get(i))
 	}
+	return sum
+}
 
-	var do func(i, j int) int
-	do = func(i, j int) int {
-		if n := memoed[i][j]; n != -1 {
-			return n
+func (t timelines) printExits(w io.Writer) {
+	last := t.exits.len() - 1
+	for i := range t.exits.len() {
+		n := t.exits.get(i)
+		if n.Sign() == 0 {
+			continue
 		}
-		if i == len(grid)-1 {
-			memoed[i][j] = 1
-			return 1
+		switch i {
+		case 0:
+			fmt.Fprintf(w, "left: %v\n", n)
+		case last:
+			fmt.Fprintf(w, "right: %v\n", n)
+		default:
+			fmt.Fprintf(w, "%d: %v\n", i-1, n)
 		}
-		// Try and go down.
-		switch grid[i+1][j] {
-		case Empty:
-			memoed[i][j] = do(i+1, j)
-			return memoed[i][j]
-		case Splitter:
-			memoed[i][j] = do(i+1, j-1) + do(i+1, j+1)
-			return memoed[i][j]
+	}
+}
+
+// partTwo counts the timelines, where every splitter a beam reaches splits
+// time in two, by going down the grid a row at a time counting how many
+// timelines have a beam going into each cell. Every S starts a beam of its
+// own. There can be a lot of timelines, so the counts turn into big.Ints if
+// they have to.
+func partTwo(grid [][]Cell) timelines {
+	w := width(grid)
+	var (
+		beams = newCounts(w, false)
+		exits = newCounts(w+2, false)
+	)
+	for _, row := range grid {
+		next := newCounts(w, beams.isBig())
+		for j := range w {
+			switch cell(row, j) {
+			case Start:
+				next.addOne(j)
+				next.addFrom(j, beams, j)
+			case Splitter:
+				if j > 0 {
+					next.addFrom(j-1, beams, j)
+				} else {
+					exits.addFrom(0, beams, j)
+				}
+				if j+1 < w {
+					next.addFrom(j+1, beams, j)
+				} else {
+					exits.addFrom(w+1, beams, j)
+				}
+			default:
+				next.addFrom(j, beams, j)
+			}
 		}
-		panic("sad time")
+		beams = next
+	}
+	for j := range w {
+		exits.addFrom(j+1, beams, j)
 	}
+	return timelines{exits: exits}
+}
 
-	start := -1
-	for i, c := range grid[0] {
-		if c == Start {
-			start = i
-			break
+// counts is a row of counts, which are uint64s until one of them overflows
+// and then big.Ints.
+type counts struct {
+	small []uint64
+	big   []*big.Int // nil until something overflows
+}
+
+func newCounts(n int, isBig bool) *counts {
+	c := &counts{small: make([]uint64, n)}
+	if isBig {
+		c.promote()
+	}
+	return c
+}
+
+func (c *counts) len() int    { return len(c.small) }
+func (c *counts) isBig() bool { return c.big != nil }
+
+// promote switches to big.Ints.
+func (c *counts) promote() {
+	c.big = make([]*big.Int, len(c.small))
+	for i, n := range c.small {
+		c.big[i] = new(big.Int).SetUint64(n)
+	}
+}
+
+// get returns count i, which mustn't be changed.
+func (c *counts) get(i int) *big.Int {
+	if c.big != nil {
+		return c.big[i]
+	}
+	return new(big.Int).SetUint64(c.small[i])
+}
+
+func (c *counts) addOne(i int) {
+	if c.big != nil {
+		c.big[i].Add(c.big[i], big.NewInt(1))
+		return
+	}
+	if c.small[i] == math.MaxUint64 {
+		c.promote()
+		c.addOne(i)
+		return
+	}
+	c.small[i]++
+}
+
+// addFrom adds count j of from to count i.
+func (c *counts) addFrom(i int, from *counts, j int) {
+	if c.big == nil && from.big == nil {
+		sum, carry := bits.Add64(c.small[i], from.small[j], 0)
+		if carry == 0 {
+			c.small[i] = sum
+			return
 		}
+		c.promote()
 	}
-	if start == -1 {
-		panic("no start")
+	if c.big == nil {
+		c.promote()
 	}
+	c.big[i].Add(c.big[i], from.get(j))
+}
 
-	return do(1, start)
+// cell returns cell j of a row, which is empty if the row is too short.
+func cell(row []Cell, j int) Cell {
+	if j < len(row) {
+		return row[j]
+	}
+	return Empty
 }
 
-func copyGrid(grid [][]Cell) [][]Cell {
-	g := make([][]Cell, len(grid))
-	for i := range g {
-		g[i] = make([]Cell, len(grid[i]))
-		copy(g[i], grid[i])
+// width is the width of the widest row.
+func width(grid [][]Cell) int {
+	w := 0
+	for _, row := range grid {
+		w = max(w, len(row))
 	}
-	return g
+	return w
 }
 
 func read(r io.Reader) ([][]Cell, error) {
@@ -153,15 +243,16 @@ func read(r io.Reader) ([][]Cell, error) {
 	return cells, nil
 }
 
-func printGrid(grid [][]Cell) {
-	var sb strings.Builder
-	for _, row := range grid {
-		for _, c := range row {
-			sb.WriteString(c.String())
-		}
-		sb.WriteByte('\n')
+// inputSyntax reads and prints a whole grid, with the cells written as they
+// are in RuneToCell.
+var inputSyntax = parse.LinesSyntax(parse.ManySyntax(parse.EnumSyntax(cellNames())))
+
+func cellNames() map[string]Cell {
+	names := make(map[string]Cell, len(RuneToCell))
+	for r, c := range RuneToCell {
+		names[string(r)] = c
 	}
-	fmt.Println(sb.String())
+	return names
 }
 
 type Cell uint8
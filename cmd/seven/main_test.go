@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+const example = `.......S.......
+...............
+.......^.......
+...............
+......^.^......
+...............
+.....^.^.^.....
+...............
+....^.^...^....
+...............
+...^.^...^.^...
+...............
+..^...^.....^..
+...............
+.^.^.^.^.^...^.
+...............
+`
+
+func readString(t *testing.T, s string) [][]Cell {
+	t.Helper()
+	grid, err := read(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return grid
+}
+
+func TestExample(t *testing.T) {
+	grid := readString(t, example)
+	if got := partOne(grid); got != 21 {
+		t.Errorf("partOne(example) = %d, want 21", got)
+	}
+	tl := partTwo(grid)
+	if got := tl.total(); got.Cmp(big.NewInt(40)) != 0 {
+		t.Errorf("partTwo(example) = %v, want 40", got)
+	}
+	var sb strings.Builder
+	tl.printExits(&sb)
+	if want := "0: 1\n2: 2\n4: 10\n6: 11\n8: 11\n10: 2\n11: 1\n12: 1\n14: 1\n"; sb.String() != want {
+		t.Errorf("exits got\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+// paths counts the timelines by following every one of them, and where
+// they leave, with -1 and the width for the sides.
+func paths(grid [][]Cell) map[int]int {
+	exits := map[int]int{}
+	var follow func(i, j int)
+	follow = func(i, j int) {
+		for ; i < len(grid); i++ {
+			if j < 0 || j >= len(grid[i]) {
+				exits[j]++
+				return
+			}
+			if cell(grid[i], j) == Splitter {
+				follow(i+1, j-1)
+				follow(i+1, j+1)
+				return
+			}
+		}
+		exits[j]++
+	}
+	for i, row := range grid {
+		for j, c := range row {
+			if c == Start {
+				follow(i+1, j)
+			}
+		}
+	}
+	return exits
+}
+
+func TestPaths(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 7))
+	for range 200 {
+		var sb strings.Builder
+		width := 1 + r.IntN(8)
+		for range 1 + r.IntN(10) {
+			for range width {
+				switch r.IntN(8) {
+				case 0:
+					sb.WriteByte('S')
+				case 1, 2, 3:
+					sb.WriteByte('^')
+				default:
+					sb.WriteByte('.')
+				}
+			}
+			sb.WriteByte('\n')
+		}
+		grid := readString(t, sb.String())
+		want := paths(grid)
+		got := partTwo(grid)
+		for i := range got.exits.len() {
+			if n := got.exits.get(i); n.Cmp(big.NewInt(int64(want[i-1]))) != 0 {
+				t.Errorf("%s: %v timelines leave at %d, want %d", sb.String(), n, i-1, want[i-1])
+			}
+		}
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	// A splitter in every cell doubles the timelines every row, even the
+	// ones that go off the sides.
+	const rows = 100
+	grid := readString(t, strings.Repeat(".", rows)+"S"+strings.Repeat(".", rows)+"\n"+
+		strings.Repeat(strings.Repeat("^", 2*rows+1)+"\n", rows))
+	tl := partTwo(grid)
+	if !tl.exits.isBig() {
+		t.Error("counts didn't overflow")
+	}
+	want := new(big.Int).Lsh(big.NewInt(1), rows)
+	if got := tl.total(); got.Cmp(want) != 0 {
+		t.Errorf("got %v timelines, want %v", got, want)
+	}
+	// The beams spread one column further each way every row, so they
+	// hit k splitters in row k.
+	if got, want := partOne(grid), rows*(rows+1)/2; got != want {
+		t.Errorf("partOne = %d, want %d", got, want)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	c := newCounts(2, false)
+	c.small[0] = 1<<64 - 1
+	c.addFrom(1, c, 0)
+	if c.isBig() {
+		t.Fatal("promoted too early")
+	}
+	c.addOne(0)
+	if !c.isBig() {
+		t.Fatal("didn't promote on overflow")
+	}
+	c.addOne(1)
+	if want, _ := new(big.Int).SetString("18446744073709551616", 10); c.get(0).Cmp(want) != 0 || c.get(1).Cmp(want) != 0 {
+		t.Errorf("got %v and %v, want %v", c.get(0), c.get(1), want)
+	}
+}
+
+func TestInputSyntax(t *testing.T) {
+	parsetest.RoundTrip(t, inputSyntax, func(in []byte) ([][]Cell, error) { return read(bytes.NewReader(in)) },
+		"inputs/example.txt", "inputs/input.txt")
+}
@@ -2,194 +2,211 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
+var verboseFlag = flag.Bool("v", false, "print every problem as it's worked out")
+
 func main() {
+	flag.Parse()
 	input, err := io.ReadAll(os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	p1, err := partOne(bytes.NewReader(input))
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Printf("Part one: %d\n", p1)
-
-	p2, err := partTwo(input)
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Printf("Part two: %d\n", p2)
-}
-
-func partTwo(input []byte) (int, error) {
-	ps, err := read2(input)
-	if err != nil {
-		return 0, err
+	ws := readWorksheet(input)
+
+	for _, part := range []struct {
+		name    string
+		reading parse.Reading
+	}{
+		{"Part one", parse.ByRows},
+		{"Part two", parse.ByColumnsRightToLeft},
+	} {
+		ps, err := ws.problems(part.reading)
+		if err != nil {
+			log.Fatalf("%s: %v", part.name, err)
+		}
+		if *verboseFlag {
+			if err := printProblems(os.Stdout, ps); err != nil {
+				log.Fatalf("%s: %v", part.name, err)
+			}
+		}
+		aoc25.PrintTiming(part.name, func() int {
+			sum, err := calculateAndSum(ps)
+			if err != nil {
+				log.Fatalf("%s: %v", part.name, err)
+			}
+			return sum
+		})
 	}
-	return calculateAndSum(ps), nil
 }
 
-func partOne(r io.Reader) (int, error) {
-	ps, err := read1(r)
-	if err != nil {
-		return 0, err
+// printProblems writes out each problem and its answer.
+func printProblems(w io.Writer, ps []problem) error {
+	for _, p := range ps {
+		x, err := p.Calculate()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%v = %d\n", p, x)
 	}
-	return calculateAndSum(ps), nil
+	return nil
 }
 
-func calculateAndSum(ps []problem) int {
+func calculateAndSum(ps []problem) (int, error) {
 	sum := 0
 	for _, p := range ps {
-		sum += p.Calculate()
+		x, err := p.Calculate()
+		if err != nil {
+			return 0, err
+		}
+		if sum, err = parse.Add(sum, x); err != nil {
+			return 0, fmt.Errorf("adding up the answers: %w", err)
+		}
 	}
-	return sum
+	return sum, nil
 }
 
-type problem struct {
-	inputs []int
-	op     op
-}
+// worksheet is the problems as they're written down: a block of column
+// aligned text for each one, with the numbers above the operator.
+type worksheet []parse.Block
 
-type op uint8
+func readWorksheet(raw []byte) worksheet {
+	return parse.Blocks(parse.SplitLines(raw))
+}
 
-const (
-	opAdd op = '+'
-	opMul op = '*'
+// worksheetSyntax reads a whole worksheet like readWorksheet, and prints it
+// back out with every line as long as the longest.
+var worksheetSyntax = parse.MapSyntax(
+	parse.BlocksSyntax(),
+	func(bs []parse.Block) worksheet { return worksheet(bs) },
+	func(ws worksheet) []parse.Block { return ws },
 )
 
-func (p problem) Calculate() int {
-	var f func(a, b int) int
-	switch p.op {
-	case opAdd:
-		f = func(a, b int) int { return a + b }
-	case opMul:
-		f = func(a, b int) int { return a * b }
-	default:
-		panic(":/")
-	}
-	x := p.inputs[0]
-	for _, y := range p.inputs[1:] {
-		x = f(x, y)
+// problems reads the numbers in each block in the given order, ByRows for
+// the way people write them or ByColumnsRightToLeft for cephalopods.
+func (ws worksheet) problems(r parse.Reading) ([]problem, error) {
+	var ps []problem
+	for _, b := range ws {
+		var (
+			last = len(b.Rows) - 1
+			p    problem
+		)
+		if last < 1 {
+			return nil, fmt.Errorf("problem at column %d: need numbers and an operator", b.Start+1)
+		}
+		name := string(bytes.TrimSpace(b.Rows[last]))
+		o, ok := operators[name]
+		if !ok {
+			return nil, fmt.Errorf("problem at column %d: unknown operator %q", b.Start+1, name)
+		}
+		p.op = o
+		numbers := parse.Block{Start: b.Start, Rows: b.Rows[:last]}
+		for _, n := range numbers.Read(r) {
+			n = bytes.TrimSpace(n)
+			if len(n) == 0 {
+				// Just the operator in this column.
+				continue
+			}
+			x, err := strconv.Atoi(string(n))
+			if err != nil {
+				return nil, fmt.Errorf("problem at column %d: %w", b.Start+1, err)
+			}
+			p.inputs = append(p.inputs, x)
+		}
+		if len(p.inputs) == 0 {
+			return nil, fmt.Errorf("problem at column %d: no numbers", b.Start+1)
+		}
+		ps = append(ps, p)
 	}
-	return x
+	return ps, nil
 }
 
-func read2(raw []byte) ([]problem, error) {
-	lines := bytes.Split(raw, []byte{'\n'})
-	if n := len(lines) - 1; len(lines[n]) == 0 {
-		lines = lines[:n]
-	}
+type problem struct {
+	inputs []int
+	op     operator
+}
 
-	lastLine := lines[len(lines)-1]
-	lines = lines[:len(lines)-1]
+// operator combines two numbers. Problems apply their operator from left to
+// right, so a - b - c is (a - b) - c.
+type operator struct {
+	name  string
+	apply func(a, b int) (int, error)
+}
 
-	newLines := make([][]byte, len(lines[0]))
-	for i := range newLines {
-		newLines[i] = make([]byte, len(lines))
-	}
-	for i := range lines {
-		for j := range lines[i] {
-			newLines[j][i] = lines[i][j]
-		}
+var operators = map[string]operator{}
+
+func init() {
+	for _, o := range []operator{
+		{"+", parse.Add},
+		{"-", parse.Sub},
+		{"*", parse.Mul},
+		{"/", parse.Div},
+		{"min", func(a, b int) (int, error) { return min(a, b), nil }},
+		{"max", func(a, b int) (int, error) { return max(a, b), nil }},
+		{"||", concat},
+	} {
+		operators[o.name] = o
 	}
+}
 
-	var (
-		p  problem
-		ps []problem
-	)
-	for _, l := range newLines {
-		l = bytes.TrimSpace(l)
-		if len(l) == 0 {
-			ps = append(ps, p)
-			p = problem{}
-			continue
-		}
-		n, err := strconv.Atoi(string(l))
-		if err != nil {
-			return nil, err
+// Calculate works out the answer, or returns an error if it doesn't fit in
+// an int.
+func (p problem) Calculate() (int, error) {
+	x := p.inputs[0]
+	for _, y := range p.inputs[1:] {
+		var err error
+		if x, err = p.op.apply(x, y); err != nil {
+			return 0, fmt.Errorf("%v: %w", p, err)
 		}
-		p.inputs = append(p.inputs, n)
 	}
-	ps = append(ps, p)
+	return x, nil
+}
 
-	i := 0
-	for _, b := range lastLine {
-		if b == ' ' {
-			continue
+// String writes the problem out as an expression.
+func (p problem) String() string {
+	var sb strings.Builder
+	for i, x := range p.inputs {
+		if i > 0 {
+			fmt.Fprintf(&sb, " %s ", p.op.name)
 		}
-		switch b {
-		case '+':
-			ps[i].op = opAdd
-		case '*':
-			ps[i].op = opMul
-		default:
-			return nil, fmt.Errorf("unknown op %q", b)
-		}
-		i++
+		sb.WriteString(strconv.Itoa(x))
 	}
-	return ps, nil
+	return sb.String()
 }
 
-func read1(r io.Reader) ([]problem, error) {
-	var (
-		problems []problem
-		scan     = bufio.NewScanner(r)
-	)
-	for first := true; scan.Scan(); first = false {
-		pieces := strings.Split(scan.Text(), " ")
-		w := 0
-		for _, p := range pieces {
-			if p == "" {
-				continue
-			}
-			pieces[w] = p
-			w++
-		}
-		pieces = pieces[:w]
-
-		if p := pieces[0]; p == "*" || p == "+" {
-			for i, p := range pieces {
-				var o op
-				switch p {
-				case "*":
-					o = opMul
-				case "+":
-					o = opAdd
-				default:
-					return nil, fmt.Errorf("unknown opeartion %q", p)
-				}
-				problems[i].op = o
-			}
-			continue // maybe break
+// concat writes b's digits after a's, so 12 || 345 is 12345.
+func concat(a, b int) (int, error) {
+	if b < 0 {
+		return 0, fmt.Errorf("%d || %d: can't put a negative number on the end", a, b)
+	}
+	shift := 10
+	for ; shift <= b; shift *= 10 {
+		if shift > math.MaxInt/10 {
+			return 0, fmt.Errorf("%d || %d: %w", a, b, parse.ErrOverflow)
 		}
-
-		for i, p := range pieces {
-			n, err := strconv.Atoi(p)
-			if err != nil {
-				return nil, err
-			}
-			if first {
-				problems = append(problems, problem{
-					inputs: []int{n},
-				})
-			} else {
-				problems[i].inputs = append(problems[i].inputs, n)
-			}
+	}
+	c, err := parse.Mul(a, shift)
+	if err == nil {
+		if a < 0 {
+			c, err = parse.Sub(c, b)
+		} else {
+			c, err = parse.Add(c, b)
 		}
-
 	}
-	if err := scan.Err(); err != nil {
-		return nil, err
+	if err != nil {
+		return 0, fmt.Errorf("%d || %d: %w", a, b, err)
 	}
-	return problems, nil
+	return c, nil
 }
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse"
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+const example = `123 328  51 64
+ 45 64  387 23
+  6 98  215 314
+*   +   *   +
+`
+
+func TestExample(t *testing.T) {
+	ws := readWorksheet([]byte(example))
+	for _, c := range []struct {
+		reading parse.Reading
+		want    int
+	}{
+		{parse.ByRows, 4277556},
+		{parse.ByColumnsRightToLeft, 3263827},
+	} {
+		ps, err := ws.problems(c.reading)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := calculateAndSum(ps)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("reading %v: got %d, want %d", c.reading, got, c.want)
+		}
+	}
+
+	ps, err := ws.problems(parse.ByColumnsRightToLeft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := printProblems(&sb, ps[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "356 * 24 * 1 = 8544\n8 + 248 + 369 = 625\n"; sb.String() != want {
+		t.Errorf("printProblems got\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestOperators(t *testing.T) {
+	ws := readWorksheet([]byte(`100  7   3    5    12   9
+ 20  2   8    1    345  4
+  3
+-    /   min  max  ||   -
+`))
+	ps, err := ws.problems(parse.ByRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{
+		77, // (100 - 20) - 3
+		3,  // 7 / 2, rounding towards zero
+		3,
+		5,
+		12345,
+		5,
+	} {
+		got, err := ps[i].Calculate()
+		if err != nil {
+			t.Errorf("%v: %v", ps[i], err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%v = %d, want %d", ps[i], got, want)
+		}
+	}
+
+	for _, bad := range []string{"1\n?\n", "1\n", "x\n+\n"} {
+		if _, err := readWorksheet([]byte(bad)).problems(parse.ByRows); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		op   string
+		a, b int
+	}{
+		{"big sum", "+", math.MaxInt, 1},
+		{"small sum", "+", math.MinInt, -1},
+		{"big difference", "-", math.MaxInt, -1},
+		{"small difference", "-", math.MinInt, 1},
+		{"big product", "*", math.MaxInt/2 + 1, 2},
+		{"negative product", "*", math.MinInt, -1},
+		{"negative product the other way", "*", -1, math.MinInt},
+		{"quotient", "/", math.MinInt, -1},
+		{"concatenation", "||", math.MaxInt / 10, 9},
+		{"long concatenation", "||", 1, math.MaxInt},
+	} {
+		if _, err := operators[c.op].apply(c.a, c.b); !errors.Is(err, parse.ErrOverflow) {
+			t.Errorf("%s: %d %s %d gave %v, want an overflow", c.name, c.a, c.op, c.b, err)
+		}
+	}
+	if _, err := operators["/"].apply(1, 0); !errors.Is(err, parse.ErrDivideByZero) {
+		t.Errorf("1 / 0 gave %v, want division by zero", err)
+	}
+	// Towards zero, not down.
+	if got, err := operators["/"].apply(-7, 2); err != nil || got != -3 {
+		t.Errorf("-7 / 2 = %d, %v, want -3", got, err)
+	}
+	// These only just fit.
+	for _, c := range []struct {
+		op   string
+		a, b int
+		want int
+	}{
+		{"+", math.MaxInt - 1, 1, math.MaxInt},
+		{"-", math.MinInt + 1, 1, math.MinInt},
+		{"*", math.MinInt / 2, 2, math.MinInt},
+		{"||", math.MaxInt / 10, 7, math.MaxInt},
+		{"||", -12, 3, -123},
+	} {
+		got, err := operators[c.op].apply(c.a, c.b)
+		if err != nil || got != c.want {
+			t.Errorf("%d %s %d = %d, %v, want %d", c.a, c.op, c.b, got, err, c.want)
+		}
+	}
+
+	ps := []problem{
+		{inputs: []int{math.MaxInt}, op: operators["+"]},
+		{inputs: []int{1}, op: operators["+"]},
+	}
+	if _, err := calculateAndSum(ps); !errors.Is(err, parse.ErrOverflow) {
+		t.Errorf("summing past MaxInt gave %v, want an overflow", err)
+	}
+}
+
+func TestWorksheetSyntax(t *testing.T) {
+	read := func(in []byte) (worksheet, error) { return readWorksheet(in), nil }
+	parsetest.RoundTrip(t, worksheetSyntax, read, "inputs/example.txt", "inputs/input.txt")
+}
@@ -2,22 +2,21 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"container/heap"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"slices"
-	"strconv"
 	"sync"
 	"sync/atomic"
 
 	"github.com/pfcm/it"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
 var workersFlag = flag.Int("workers", 10, "parallelism for part 2")
@@ -72,68 +71,115 @@ func partTwo(ms []machine) int32 {
 	return n.Load()
 }
 
+// read reads all of the machines, reporting every line that's wrong rather
+// than just the first.
 func read(r io.Reader) ([]machine, error) {
 	var (
 		machines []machine
-		scan     = bufio.NewScanner(r)
+		errs     []error
+		line     = 0
 	)
-	for scan.Scan() {
-		m, err := fromBytes(scan.Bytes())
+	for in, err := range parse.Lines(r, machineSyntax.Parser) {
+		line++
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			continue
+		}
+		m, err := in.machine()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
 		}
 		machines = append(machines, m)
 	}
-	if err := scan.Err(); err != nil {
+	if err := errors.Join(errs...); err != nil {
 		return nil, err
 	}
 	return machines, nil
 }
 
 type machine struct {
+	lights       int      // how many there are
 	targetLights uint16   // 0 bit means we want it off, 1 on
 	buttons      []uint16 // 0 bit means no change, 1 means it toggles
 	joltages     []int    // actually numbers
 }
 
-func fromBytes(b []byte) (machine, error) {
-	pieces := bytes.Split(b, []byte{' '})
-	if len(pieces) < 3 {
-		return machine{}, fmt.Errorf("invalid machine: %q", b)
+// machineInput is a machine as it appears in the input.
+type machineInput struct {
+	Lights   string
+	Buttons  [][]uint8 `sep:" " brackets1:"()"`
+	Joltages []int     `brackets:"{}"`
+}
+
+// machineSyntax reads machines, and writes them back out the same way.
+var machineSyntax = parse.FormatSyntax[machineInput]("[{lights}] {buttons} {joltages}")
+
+// inputSyntax reads and prints a whole input, a machine to a line.
+var inputSyntax = parse.LinesSyntax(machineSyntax)
+
+func (in machineInput) machine() (machine, error) {
+	if len(in.Lights) > 16 {
+		return machine{}, fmt.Errorf("too many lights: %q", in.Lights)
 	}
 	lights := uint16(0)
-	for i, l := range pieces[0][1 : len(pieces[0])-1] {
-		if l == '#' {
+	for i, l := range in.Lights {
+		switch l {
+		case '#':
 			lights |= 1 << i
+		case '.':
+		default:
+			return machine{}, fmt.Errorf("invalid light %q in %q", l, in.Lights)
 		}
 	}
 	var buttons []uint16
-	for _, button := range pieces[1 : len(pieces)-1] {
+	for _, ns := range in.Buttons {
 		butt := uint16(0)
-		ns, err := numbers(button)
-		if err != nil {
-			return machine{}, err
-		}
 		for _, n := range ns {
+			if int(n) >= len(in.Lights) {
+				return machine{}, fmt.Errorf("button %v: no light %d", ns, n)
+			}
 			butt |= 1 << n
 		}
 		buttons = append(buttons, butt)
 	}
-
-	js, err := numbers(pieces[len(pieces)-1])
-	if err != nil {
-		return machine{}, err
-	}
-	m := machine{
+	return machine{
+		lights:       len(in.Lights),
 		targetLights: lights,
 		buttons:      buttons,
-		joltages:     js,
+		joltages:     in.Joltages,
+	}, nil
+}
+
+// input is the opposite of machineInput.machine.
+func (m machine) input() machineInput {
+	lights := make([]byte, m.lights)
+	for i := range lights {
+		lights[i] = '.'
+		if m.targetLights&(1<<i) != 0 {
+			lights[i] = '#'
+		}
 	}
-	return m, nil
+	in := machineInput{Lights: string(lights), Joltages: m.joltages}
+	for _, b := range m.buttons {
+		var ns []uint8
+		for i := range m.lights {
+			if b&(1<<i) != 0 {
+				ns = append(ns, uint8(i))
+			}
+		}
+		in.Buttons = append(in.Buttons, ns)
+	}
+	return in
 }
 
+// String writes m out as it would appear in the input.
 func (m machine) String() string {
-	return fmt.Sprintf("[%08b] %08b %v", m.targetLights, m.buttons, m.joltages)
+	b, err := machineSyntax.Bytes(m.input())
+	if err != nil {
+		return fmt.Sprintf("invalid machine: %v", err)
+	}
+	return string(b)
 }
 
 func (m machine) turnOn() int {
@@ -239,18 +285,6 @@ func (m machine) setJoltages() int {
 	panic("max iterations")
 }
 
-func numbers(b []byte) ([]int, error) {
-	var nums []int
-	for num := range bytes.SplitSeq(b[1:len(b)-1], []byte{','}) {
-		n, err := strconv.Atoi(string(num))
-		if err != nil {
-			return nil, err
-		}
-		nums = append(nums, n)
-	}
-	return nums, nil
-}
-
 type jnode struct {
 	h        float64
 	length   int
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+// TestRoundTrip checks that printing the machines gives back the lines they
+// were read from.
+func TestRoundTrip(t *testing.T) {
+	for _, name := range []string{"inputs/example.txt", "inputs/input.txt"} {
+		input, err := os.ReadFile(name)
+		if err != nil {
+			t.Skip(err)
+		}
+		machines, err := read(bytes.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		lines := bytes.Split(bytes.TrimSuffix(input, []byte("\n")), []byte("\n"))
+		if len(machines) != len(lines) {
+			t.Fatalf("%s: read %d machines from %d lines", name, len(machines), len(lines))
+		}
+		for i, m := range machines {
+			if got := m.String(); got != string(lines[i]) {
+				t.Errorf("%s:%d: printed %q, want %q", name, i+1, got, lines[i])
+			}
+		}
+	}
+}
+
+func TestInputSyntax(t *testing.T) {
+	read := func(in []byte) ([]machineInput, error) {
+		machines, err := read(bytes.NewReader(in))
+		if err != nil {
+			return nil, err
+		}
+		inputs := make([]machineInput, len(machines))
+		for i, m := range machines {
+			inputs[i] = m.input()
+		}
+		return inputs, nil
+	}
+	parsetest.RoundTrip(t, inputSyntax, read, "inputs/example.txt", "inputs/input.txt")
+}
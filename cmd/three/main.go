@@ -3,86 +3,136 @@ package main
 
 import (
 	"bufio"
+	"flag"
+	"fmt"
 	"io"
 	"log"
-	"math"
+	"math/big"
+	"math/bits"
 	"os"
 
 	"github.com/pfcm/aoc25"
+	"github.com/pfcm/aoc25/parse"
 )
 
+var digitsFlag = flag.Int("digits", 0, "if set, turn on this many batteries in each bank instead of doing the two parts")
+
 func main() {
+	flag.Parse()
 	banks, err := read(os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
-	aoc25.PrintTiming("Part one", func() int { return partOne(banks) })
-	aoc25.PrintTiming("Part two", func() uint64 { return partTwo(banks) })
+	if *digitsFlag > 0 {
+		printTotal("Joltage", banks, *digitsFlag)
+		return
+	}
+	printTotal("Part one", banks, 2)
+	printTotal("Part two", banks, 12)
+}
+
+func printTotal(name string, banks [][]uint8, k int) {
+	for i, bank := range banks {
+		if k < 1 || k > len(bank) {
+			log.Fatalf("%s: bank %d has %d batteries, can't turn on %d", name, i+1, len(bank), k)
+		}
+	}
+	aoc25.PrintTiming(name, func() *big.Int {
+		sum, err := total(banks, k)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return sum
+	})
 }
 
-func partTwo(banks [][]uint8) uint64 {
+// maxUint64Digits is the most digits that always fit in a uint64.
+const maxUint64Digits = 19
+
+// total sums the biggest joltage each bank can make with k batteries. It
+// only uses big.Int arithmetic when it has to: for joltages longer than
+// maxUint64Digits, or once the sum itself overflows.
+func total(banks [][]uint8, k int) (*big.Int, error) {
 	var (
-		sum = uint64(0)
-		tmp [12]uint8
+		sum      uint64
+		overflow = new(big.Int)
 	)
-	for _, bank := range banks {
-		// Same as below, we just have to do it twelve times instead of
-		// two.
-		start := 0
-		for i := range 12 {
-			var (
-				end      = len(bank) - 11 + i // one after last available digit
-				max      uint8
-				maxIndex = start
-			)
-			for j := start; j < end; j++ {
-				b := bank[j]
-				if b > max {
-					max = b
-					maxIndex = j
-				}
+	for i, bank := range banks {
+		positions, err := choose(bank, k)
+		if err != nil {
+			return nil, fmt.Errorf("bank %d: %w", i+1, err)
+		}
+		if k > maxUint64Digits {
+			j, err := bigJoltage(bank, positions)
+			if err != nil {
+				return nil, fmt.Errorf("bank %d: %w", i+1, err)
 			}
-			tmp[i] = max
-			start = maxIndex + 1
+			overflow.Add(overflow, j)
+			continue
 		}
-		inc := uint64(0)
-		for i, b := range tmp {
-			r := uint64(math.Pow10(11 - i))
-			inc += uint64(b) * r
+		var carry uint64
+		sum, carry = bits.Add64(sum, joltage(bank, positions), 0)
+		if carry != 0 {
+			overflow.Add(overflow, new(big.Int).Lsh(big.NewInt(1), 64))
 		}
-		sum += inc
 	}
-	return sum
+	return overflow.Add(overflow, new(big.Int).SetUint64(sum)), nil
 }
 
-func partOne(banks [][]uint8) int {
-	sum := 0
-	for _, bank := range banks {
-		// We're always going to want to start with the highest number
-		// (that isn't the last one). The only trick will be what to do
-		// when there are duplicates, although in that case we should
-		// just take the first.
-		var (
-			max      uint8
-			maxIndex int
-		)
-		for i, b := range bank[:len(bank)-1] {
-			if b > max {
-				max = b
-				maxIndex = i
-			}
-		}
-		var next uint8
-		for _, b := range bank[maxIndex+1:] {
-			if b > next {
-				next = b
-			}
+// choose picks the k batteries in a bank that make the biggest joltage, and
+// returns their positions in order. It keeps a stack of the batteries chosen
+// so far, where a new battery knocks off any smaller ones on the top as long
+// as there are enough batteries left after it to make up the k. That leaves
+// the digits as big as they can be from the left, which is what makes the
+// number biggest. Ties keep the earlier battery, which leaves more choice
+// later on.
+func choose(bank []uint8, k int) ([]int, error) {
+	if k < 1 || k > len(bank) {
+		return nil, fmt.Errorf("can't turn on %d of %d batteries", k, len(bank))
+	}
+	var (
+		stack = make([]int, 0, len(bank))
+		drop  = len(bank) - k // how many more we can leave off
+	)
+	for i, b := range bank {
+		for drop > 0 && len(stack) > 0 && bank[stack[len(stack)-1]] < b {
+			stack = stack[:len(stack)-1]
+			drop--
 		}
-		sum += int(max)*10 + int(next)
+		stack = append(stack, i)
+	}
+	return stack[:k], nil
+}
+
+// joltage is the number made by the batteries at the positions, which must
+// fit in a uint64.
+func joltage(bank []uint8, positions []int) uint64 {
+	j := uint64(0)
+	for _, p := range positions {
+		j = j*10 + uint64(bank[p])
 	}
-	return sum
+	return j
 }
 
+// bigJoltage is joltage for any number of batteries. It fails if any of
+// them isn't a single digit.
+func bigJoltage(bank []uint8, positions []int) (*big.Int, error) {
+	digits := make([]byte, len(positions))
+	for i, p := range positions {
+		digits[i] = '0' + bank[p]
+	}
+	j, ok := new(big.Int).SetString(string(digits), 10)
+	if !ok {
+		return nil, fmt.Errorf("batteries %v aren't all digits", bank)
+	}
+	return j, nil
+}
+
+// inputSyntax reads and prints a whole input, a bank of digits to a line.
+var inputSyntax = parse.LinesSyntax(parse.ManySyntax(parse.EnumSyntax(map[string]uint8{
+	"0": 0, "1": 1, "2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
+})))
+
 func read(r io.Reader) ([][]uint8, error) {
 	var (
 		banks [][]uint8
@@ -91,8 +141,11 @@ func read(r io.Reader) ([][]uint8, error) {
 	for scan.Scan() {
 		line := scan.Bytes()
 		bank := make([]uint8, len(line))
-		for i := range bank {
-			bank[i] = line[i] - '0'
+		for i, c := range line {
+			if c < '0' || c > '9' {
+				return nil, fmt.Errorf("line %d: %q isn't a digit", len(banks)+1, c)
+			}
+			bank[i] = c - '0'
 		}
 		banks = append(banks, bank)
 	}
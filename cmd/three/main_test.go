@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+func readString(t *testing.T, s string) [][]uint8 {
+	t.Helper()
+	banks, err := read(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return banks
+}
+
+func TestTotal(t *testing.T) {
+	banks := readString(t, "987654321111111\n811111111111119\n234234234234278\n818181911112111\n")
+	for _, c := range []struct {
+		k    int
+		want string
+	}{
+		{2, "357"},
+		{12, "3121910778619"},
+		// Every battery in every bank.
+		{15, "2851181577568619"},
+	} {
+		got, err := total(banks, c.k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != c.want {
+			t.Errorf("total(example, %d) = %v, want %s", c.k, got, c.want)
+		}
+	}
+	if _, err := total(banks, 16); err == nil {
+		t.Error("total(example, 16): no error")
+	}
+}
+
+func TestTotalOverflow(t *testing.T) {
+	// Nineteen nines fit in a uint64 but two of them don't, and twenty
+	// nines don't either.
+	banks := readString(t, strings.Repeat(strings.Repeat("9", 20)+"\n", 2))
+	for _, k := range []int{19, 20} {
+		want, _ := new(big.Int).SetString(strings.Repeat("9", k), 10)
+		want.Mul(want, big.NewInt(2))
+		got, err := total(banks, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("total(nines, %d) = %v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestNotDigits(t *testing.T) {
+	for _, in := range []string{"12a4\n", "123\n45 6\n", "-1\n"} {
+		if banks, err := read(strings.NewReader(in)); err == nil {
+			t.Errorf("read(%q) = %v, want an error", in, banks)
+		}
+	}
+	// Banks that didn't come from read can still have them.
+	bank := slices.Repeat([]uint8{9}, 20)
+	bank[3] = 10
+	if got, err := total([][]uint8{bank}, 20); err == nil {
+		t.Errorf("total with a 10 = %v, want an error", got)
+	}
+}
+
+// best finds the biggest joltage by trying every way to pick k batteries.
+func best(bank []uint8, k int) uint64 {
+	var (
+		max uint64
+		try func(start int, j uint64, left int)
+	)
+	try = func(start int, j uint64, left int) {
+		if left == 0 {
+			max = maxOf(max, j)
+			return
+		}
+		for i := start; i <= len(bank)-left; i++ {
+			try(i+1, j*10+uint64(bank[i]), left-1)
+		}
+	}
+	try(0, 0, k)
+	return max
+}
+
+func maxOf(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func TestChoose(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 3))
+	for range 500 {
+		bank := make([]uint8, 1+r.IntN(12))
+		for i := range bank {
+			// Few digits, so there are lots of ties.
+			bank[i] = uint8(1 + r.IntN(3))
+		}
+		for k := 1; k <= len(bank); k++ {
+			positions, err := choose(bank, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(positions) != k || !slices.IsSorted(positions) {
+				t.Fatalf("choose(%v, %d) = %v, not %d positions in order", bank, k, positions, k)
+			}
+			for i := 1; i < k; i++ {
+				if positions[i] == positions[i-1] {
+					t.Fatalf("choose(%v, %d) = %v, uses a battery twice", bank, k, positions)
+				}
+			}
+			if got, want := joltage(bank, positions), best(bank, k); got != want {
+				t.Errorf("choose(%v, %d) makes %d, want %d", bank, k, got, want)
+			}
+			if got, err := bigJoltage(bank, positions); err != nil || got.Uint64() != joltage(bank, positions) {
+				t.Errorf("bigJoltage(%v, %v) = %v, %v; want %d", bank, positions, got, err, joltage(bank, positions))
+			}
+		}
+	}
+}
+
+func TestInputSyntax(t *testing.T) {
+	parsetest.RoundTrip(t, inputSyntax, func(in []byte) ([][]uint8, error) { return read(bytes.NewReader(in)) },
+		"inputs/example.txt", "inputs/input.txt")
+}
@@ -3,111 +3,333 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
-	"iter"
 	"log"
+	"math/big"
 	"os"
 	"slices"
 	"strconv"
 
 	"github.com/pfcm/aoc25"
-	"github.com/pfcm/it"
+	"github.com/pfcm/aoc25/parse"
+)
+
+var (
+	baseFlag  = flag.Int("base", 10, "base the IDs are written in, from 2 to 36")
+	minFlag   = flag.Int("min", 0, "if set, look for IDs repeated at least this many times instead of doing the two parts")
+	maxFlag   = flag.Int("max", 0, "the most repeats to look for with -min, or 0 for no limit")
+	exactFlag = flag.Int("exact", 0, "if set, look for IDs repeated exactly this many times instead of doing the two parts")
+	listFlag  = flag.Bool("list", false, "list the invalid IDs in each range")
+	checkFlag = flag.Bool("check", false, "check the sums against a search of every ID, which can be very slow")
 )
 
 func main() {
+	flag.Parse()
+	rules, err := flagRules(*baseFlag, *minFlag, *maxFlag, *exactFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	ranges, err := read(os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
-	aoc25.PrintTiming("Part one", func() uint64 { return partOne(ranges) })
-	aoc25.PrintTiming("Part two", func() uint64 { return partTwo(ranges) })
+	for _, r := range rules {
+		if err := r.rule.validate(); err != nil {
+			log.Fatal(err)
+		}
+		aoc25.PrintTiming(r.name, func() *big.Int { return sumInvalid(ranges, r.rule) })
+		if *checkFlag {
+			if got, want := sumInvalid(ranges, r.rule), bruteForce(ranges, r.rule); got.Cmp(want) != 0 {
+				log.Fatalf("%s: got %v, but checking every ID gives %v", r.name, got, want)
+			}
+		}
+		if *listFlag {
+			for _, rng := range ranges {
+				fmt.Printf("%d-%d:", rng.A, rng.B)
+				for _, id := range invalidIDs(rng, r.rule) {
+					fmt.Printf(" %d", id)
+					if r.rule.base != 10 {
+						fmt.Printf(" (%s)", strconv.FormatUint(id, r.rule.base))
+					}
+				}
+				fmt.Println()
+			}
+		}
+	}
+}
+
+// namedRule is a rule along with what to call its answer.
+type namedRule struct {
+	name string
+	rule rule
 }
 
-func partOne(ranges []Range) uint64 {
-	valid := func(b []byte) bool {
-		if len(b)%2 == 1 {
-			// odd length strings can't be made of a repeat
-			return true
+// flagRules returns the rules that the -base, -min, -max and -exact flags ask
+// for: the two parts, unless -exact or -min pick out a single rule.
+func flagRules(base, minimum, maximum, exact int) ([]namedRule, error) {
+	switch {
+	case exact > 0 && (minimum > 0 || maximum > 0):
+		return nil, errors.New("-exact can't be used with -min or -max")
+	case maximum != 0 && minimum <= 0:
+		return nil, errors.New("-max can only be used with -min")
+	case exact > 0:
+		return []namedRule{{"Invalid", rule{base: base, minRepeats: exact, maxRepeats: exact}}}, nil
+	case minimum > 0:
+		return []namedRule{{"Invalid", rule{base: base, minRepeats: minimum, maxRepeats: maximum}}}, nil
+	}
+	return []namedRule{
+		{"Part one", rule{base: base, minRepeats: 2, maxRepeats: 2}},
+		{"Part two", rule{base: base, minRepeats: 2}},
+	}, nil
+}
+
+// rule says which IDs are invalid: those that, written in base, are a block
+// of digits repeated k times for minRepeats <= k <= maxRepeats. maxRepeats
+// is 0 if there's no limit.
+type rule struct {
+	base       int
+	minRepeats int
+	maxRepeats int
+}
+
+func (r rule) validate() error {
+	switch {
+	case r.base < 2 || r.base > 36:
+		return fmt.Errorf("base %d is not between 2 and 36", r.base)
+	case r.minRepeats < 2:
+		return fmt.Errorf("need at least 2 repeats, not %d", r.minRepeats)
+	case r.maxRepeats != 0 && r.maxRepeats < r.minRepeats:
+		return fmt.Errorf("at most %d repeats is fewer than at least %d", r.maxRepeats, r.minRepeats)
+	}
+	return nil
+}
+
+// allows reports whether k repeats make an ID invalid.
+func (r rule) allows(k int) bool {
+	return k >= r.minRepeats && (r.maxRepeats == 0 || k <= r.maxRepeats)
+}
+
+// repeats returns the numbers of repeats that an l digit ID can be made of
+// under the rule.
+func (r rule) repeats(l int) []int {
+	var ks []int
+	for k := 2; k <= l; k++ {
+		if l%k == 0 && r.allows(k) {
+			ks = append(ks, k)
 		}
-		n := len(b) / 2
-		return !bytes.Equal(b[:n], b[n:])
 	}
-	sum := uint64(0)
-	for num, i := range iterRanges(ranges) {
-		if !valid(num) {
-			sum += i
+	return ks
+}
+
+// sumInvalid sums the IDs in all of the ranges that the rule says are
+// invalid. Rather than looking at every ID, it works out the sum of the ones
+// of each length directly: an l digit ID made of a p digit block repeated is
+// the block times 1 + b^p + b^2p + ... + b^(l-p), where b is the base, so
+// they're an arithmetic series.
+func sumInvalid(ranges []Range, r rule) *big.Int {
+	sum := new(big.Int)
+	for _, rng := range ranges {
+		for _, p := range lengths(rng, r.base) {
+			sum.Add(sum, sumLength(p.lo, p.hi, p.l, r))
 		}
 	}
 	return sum
 }
 
-func partTwo(ranges []Range) uint64 {
-	valid := func(b []byte) bool {
-		for d := 2; len(b)/d > 0; d++ {
-			if len(b)%d != 0 {
-				continue
-			}
-			var (
-				n   = len(b) / d
-				seq = b[:n]
-			)
-			if it.All(it.Map(it.Batch(slices.Values(b), n), func(s []byte) bool {
-				return bytes.Equal(s, seq)
-			})) {
-				return false
-			}
+// piece is the part of a range where the IDs have l digits.
+type piece struct {
+	l      int
+	lo, hi *big.Int
+}
+
+// lengths splits a range into pieces with the same number of digits.
+func lengths(rng Range, base int) []piece {
+	var pieces []piece
+	for l := numDigits(rng.A, base); l <= numDigits(rng.B, base); l++ {
+		var (
+			lo = bigMax(new(big.Int).SetUint64(rng.A), pow(base, l-1))
+			hi = bigMin(new(big.Int).SetUint64(rng.B), new(big.Int).Sub(pow(base, l), one))
+		)
+		if lo.Cmp(hi) <= 0 {
+			pieces = append(pieces, piece{l, lo, hi})
 		}
-		return true
 	}
-	sum := uint64(0)
-	for num, i := range iterRanges(ranges) {
-		if !valid(num) {
-			sum += i
+	return pieces
+}
+
+// sumLength sums the invalid IDs in [lo, hi], which all have l digits. An ID
+// can be made of repeats in more than one way (222222 is 2 six times, 22
+// three times and 222 twice), so the sums for each number of repeats are
+// combined by inclusion-exclusion: the IDs made of both k and j repeats are
+// the ones made of lcm(k, j) repeats.
+func sumLength(lo, hi *big.Int, l int, r rule) *big.Int {
+	ks := r.repeats(l)
+	sum := new(big.Int)
+	for subset := 1; subset < 1<<len(ks); subset++ {
+		var (
+			k    = 1
+			size = 0
+		)
+		for i, ki := range ks {
+			if subset&(1<<i) != 0 {
+				k = lcm(k, ki)
+				size++
+			}
+		}
+		first, last, mult := blocks(lo, hi, l, l/k, r.base)
+		if first.Cmp(last) > 0 {
+			continue
+		}
+		// (first + last) * (last - first + 1) / 2 * mult
+		count := new(big.Int).Sub(last, first)
+		count.Add(count, one)
+		s := new(big.Int).Add(first, last)
+		s.Mul(s, count).Rsh(s, 1).Mul(s, mult)
+		if size%2 == 0 {
+			sum.Sub(sum, s)
+		} else {
+			sum.Add(sum, s)
 		}
 	}
 	return sum
 }
 
-func iterRanges(ranges []Range) iter.Seq2[[]byte, uint64] {
-	return func(yield func([]byte, uint64) bool) {
-		var scratch []byte
-		for _, r := range ranges {
-			for i := r.a; i <= r.b; i++ {
-				scratch = strconv.AppendUint(scratch[:0], i, 10)
-				if !yield(scratch, i) {
-					return
+// blocks returns the range of p digit blocks that, repeated, make an ID in
+// [lo, hi], which all have l digits, along with what to multiply a block by
+// to repeat it. There aren't any if first > last.
+func blocks(lo, hi *big.Int, l, p, base int) (first, last, mult *big.Int) {
+	// mult is 1 + b^p + b^2p + ... + b^(l-p).
+	mult = new(big.Int).Sub(pow(base, l), one)
+	mult.Quo(mult, new(big.Int).Sub(pow(base, p), one))
+
+	// The smallest block that's big enough, rounding up, and the biggest
+	// that isn't too big.
+	first = new(big.Int).Add(lo, mult)
+	first.Sub(first, one).Quo(first, mult)
+	first = bigMax(first, pow(base, p-1))
+	last = new(big.Int).Quo(hi, mult)
+	last = bigMin(last, new(big.Int).Sub(pow(base, p), one))
+	return first, last, mult
+}
+
+// invalidIDs returns the invalid IDs in a range, in order.
+func invalidIDs(rng Range, r rule) []uint64 {
+	var ids []uint64
+	for _, p := range lengths(rng, r.base) {
+		start := len(ids)
+		for _, k := range r.repeats(p.l) {
+			first, last, mult := blocks(p.lo, p.hi, p.l, p.l/k, r.base)
+			for b := first.Uint64(); first.Cmp(last) <= 0 && b <= last.Uint64(); b++ {
+				ids = append(ids, b*mult.Uint64())
+			}
+		}
+		slices.Sort(ids[start:])
+	}
+	return slices.Compact(ids)
+}
+
+// bruteForce sums the invalid IDs in the ranges by looking at every one of
+// them, to check sumInvalid.
+func bruteForce(ranges []Range, r rule) *big.Int {
+	var (
+		sum     = new(big.Int)
+		scratch []byte
+	)
+	for _, rng := range ranges {
+		for id := rng.A; ; id++ {
+			scratch = strconv.AppendUint(scratch[:0], id, r.base)
+			for _, k := range r.repeats(len(scratch)) {
+				n := len(scratch) / k
+				if bytes.Equal(scratch[:len(scratch)-n], scratch[n:]) {
+					sum.Add(sum, new(big.Int).SetUint64(id))
+					break
 				}
 			}
+			if id == rng.B {
+				// Before it wraps around.
+				break
+			}
 		}
 	}
+	return sum
+}
+
+var one = big.NewInt(1)
+
+func pow(base, n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(n)), nil)
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+func bigMin(a, b *big.Int) *big.Int {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+func numDigits(n uint64, base int) int {
+	d := 1
+	for ; n >= uint64(base); n /= uint64(base) {
+		d++
+	}
+	return d
+}
+
+func lcm(a, b int) int {
+	g, x := a, b
+	for x != 0 {
+		g, x = x, g%x
+	}
+	return a / g * b
 }
 
 type Range struct {
-	a, b uint64
+	A, B uint64
 }
 
+// input is the whole (single line) input.
+type input struct {
+	Ranges []Range `format:"{a}-{b}"`
+}
+
+// inputDecoder is what parseInput, in parse_input.go, is generated from.
+//
+//go:generate go run ../aoc generate -decoder inputDecoder -func parseInput
+var inputDecoder = parse.MustDecoder[input]("{ranges}")
+
+var generatedInputDecoder = parse.DecoderOf(parseInput)
+
+// inputSyntax reads and prints a whole input, ending with a newline.
+var inputSyntax = parse.MapSyntax(
+	parse.SeqLSyntax(parse.FormatSyntax[input]("{ranges}"), parse.LiteralSyntax("\n")),
+	func(in input) []Range { return in.Ranges },
+	func(ranges []Range) input { return input{Ranges: ranges} },
+)
+
 func read(r io.Reader) ([]Range, error) {
+	return readWith(r, generatedInputDecoder)
+}
+
+// readWith is read with a particular decoder for the input.
+func readWith(r io.Reader, d *parse.Decoder[input]) ([]Range, error) {
 	raw, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	raw = bytes.TrimSpace(raw)
-	var ranges []Range
-	for pair := range bytes.SplitSeq(raw, []byte{','}) {
-		rawA, rawB, ok := bytes.Cut(pair, []byte{'-'})
-		if !ok {
-			return nil, fmt.Errorf("invalid range: %q", pair)
-		}
-		a, err := strconv.ParseUint(string(rawA), 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		b, err := strconv.ParseUint(string(rawB), 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		ranges = append(ranges, Range{a: a, b: b})
+	in, err := d.Decode(bytes.TrimSpace(raw))
+	if err != nil {
+		return nil, err
 	}
-	return ranges, nil
+	return in.Ranges, nil
 }
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse"
+	"github.com/pfcm/aoc25/parse/parsetest"
+)
+
+// search sums the IDs in the ranges for which invalid is true, by looking at
+// every one of them written in base.
+func search(ranges []Range, base int, invalid func([]byte) bool) *big.Int {
+	var (
+		sum     = new(big.Int)
+		scratch []byte
+	)
+	for _, r := range ranges {
+		for i := r.A; ; i++ {
+			scratch = strconv.AppendUint(scratch[:0], i, base)
+			if invalid(scratch) {
+				sum.Add(sum, new(big.Int).SetUint64(i))
+			}
+			if i == r.B {
+				// Before it wraps around.
+				break
+			}
+		}
+	}
+	return sum
+}
+
+// repeated reports whether b is made of a block repeated k times.
+func repeated(b []byte, k int) bool {
+	if len(b)%k != 0 {
+		return false
+	}
+	n := len(b) / k
+	return bytes.Equal(b[:len(b)-n], b[n:])
+}
+
+var (
+	partOne = rule{base: 10, minRepeats: 2, maxRepeats: 2}
+	partTwo = rule{base: 10, minRepeats: 2}
+)
+
+func TestParts(t *testing.T) {
+	ranges := []Range{
+		{11, 22}, {95, 115}, {998, 1012}, {1188511880, 1188511890}, {222220, 222224},
+		{1698522, 1698528}, {446443, 446449}, {38593856, 38593862}, {565653, 565659},
+		{824824821, 824824827}, {2121212118, 2121212124},
+		// And some more with lots of IDs that repeat in several ways.
+		{0, 200000}, {999990, 1111112}, {2222222222, 2222222222},
+	}
+	twice := func(b []byte) bool { return repeated(b, 2) }
+	atLeastTwice := func(b []byte) bool {
+		for k := 2; k <= len(b); k++ {
+			if repeated(b, k) {
+				return true
+			}
+		}
+		return false
+	}
+	if got, want := sumInvalid(ranges, partOne), search(ranges, 10, twice); got.Cmp(want) != 0 {
+		t.Errorf("part one = %v, brute force gives %v", got, want)
+	}
+	if got, want := sumInvalid(ranges, partTwo), search(ranges, 10, atLeastTwice); got.Cmp(want) != 0 {
+		t.Errorf("part two = %v, brute force gives %v", got, want)
+	}
+}
+
+func TestWideRanges(t *testing.T) {
+	// Right at the top of the uint64 range, where 10^20 doesn't fit. The
+	// first range has 18446744071844674407 in it.
+	top := []Range{{18446744071844600000, 18446744071844700000}, {18446744073709000000, 18446744073709551615}}
+	if got, want := sumInvalid(top, partTwo), search(top, 10, func(b []byte) bool {
+		return repeated(b, 2) || repeated(b, 4) || repeated(b, 5) || repeated(b, 10) || repeated(b, 20)
+	}); got.Cmp(want) != 0 {
+		t.Errorf("part two = %v, brute force gives %v", got, want)
+	}
+
+	// And everything, which would take forever one ID at a time.
+	all := []Range{{0, 18446744073709551615}}
+	if one, two := sumInvalid(all, partOne), sumInvalid(all, partTwo); one.Cmp(two) >= 0 {
+		t.Errorf("part one = %v, which should be less than part two = %v", one, two)
+	}
+}
+
+func TestRules(t *testing.T) {
+	ranges := []Range{{0, 300000}, {1 << 30, 1<<30 + 100000}, {18446744073709000000, 18446744073709551615}}
+	for _, r := range []rule{
+		{base: 10, minRepeats: 3, maxRepeats: 3},
+		{base: 10, minRepeats: 3},
+		{base: 10, minRepeats: 2, maxRepeats: 4},
+		{base: 2, minRepeats: 2},
+		{base: 2, minRepeats: 3, maxRepeats: 5},
+		{base: 7, minRepeats: 2, maxRepeats: 2},
+		{base: 16, minRepeats: 2},
+		{base: 36, minRepeats: 2},
+	} {
+		invalid := func(b []byte) bool {
+			for k := r.minRepeats; k <= len(b) && (r.maxRepeats == 0 || k <= r.maxRepeats); k++ {
+				if repeated(b, k) {
+					return true
+				}
+			}
+			return false
+		}
+		want := search(ranges, r.base, invalid)
+		if got := sumInvalid(ranges, r); got.Cmp(want) != 0 {
+			t.Errorf("sumInvalid(%+v) = %v, brute force gives %v", r, got, want)
+		}
+		if got := bruteForce(ranges, r); got.Cmp(want) != 0 {
+			t.Errorf("bruteForce(%+v) = %v, want %v", r, got, want)
+		}
+		listed := new(big.Int)
+		for _, rng := range ranges {
+			ids := invalidIDs(rng, r)
+			if !slices.IsSorted(ids) {
+				t.Errorf("invalidIDs(%v, %+v) = %v, not in order", rng, r, ids)
+			}
+			for _, id := range ids {
+				if id < rng.A || id > rng.B || !invalid(strconv.AppendUint(nil, id, r.base)) {
+					t.Errorf("invalidIDs(%v, %+v) has %d, which isn't invalid", rng, r, id)
+				}
+				listed.Add(listed, new(big.Int).SetUint64(id))
+			}
+		}
+		if listed.Cmp(want) != 0 {
+			t.Errorf("invalidIDs(%+v) sum to %v, want %v", r, listed, want)
+		}
+	}
+}
+
+func TestInvalidIDs(t *testing.T) {
+	if got, want := invalidIDs(Range{95, 115}, partTwo), []uint64{99, 111}; !slices.Equal(got, want) {
+		t.Errorf("invalidIDs(95-115) = %v, want %v", got, want)
+	}
+	if got, want := invalidIDs(Range{222220, 222224}, partTwo), []uint64{222222}; !slices.Equal(got, want) {
+		t.Errorf("invalidIDs(222220-222224) = %v, want %v", got, want)
+	}
+	// 0b101101, 0b110110 and 0b111111.
+	if got, want := invalidIDs(Range{40, 63}, rule{base: 2, minRepeats: 2, maxRepeats: 2}), []uint64{45, 54, 63}; !slices.Equal(got, want) {
+		t.Errorf("invalidIDs(40-63, base 2) = %v, want %v", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, r := range []rule{
+		{base: 1, minRepeats: 2},
+		{base: 37, minRepeats: 2},
+		{base: 10, minRepeats: 1},
+		{base: 10, minRepeats: 3, maxRepeats: 2},
+	} {
+		if err := r.validate(); err == nil {
+			t.Errorf("%+v: no error", r)
+		}
+	}
+}
+
+func TestFlagRules(t *testing.T) {
+	for _, c := range []struct {
+		min, max, exact int
+		want            []rule // nil for an error
+	}{
+		{0, 0, 0, []rule{partOne, partTwo}},
+		{3, 0, 0, []rule{{base: 10, minRepeats: 3}}},
+		{3, 5, 0, []rule{{base: 10, minRepeats: 3, maxRepeats: 5}}},
+		{0, 0, 4, []rule{{base: 10, minRepeats: 4, maxRepeats: 4}}},
+		{3, 0, 4, nil},
+		{0, 5, 4, nil},
+		{3, 5, 4, nil},
+		{0, 5, 0, nil},
+	} {
+		named, err := flagRules(10, c.min, c.max, c.exact)
+		if c.want == nil {
+			if err == nil {
+				t.Errorf("-min %d -max %d -exact %d: no error", c.min, c.max, c.exact)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("-min %d -max %d -exact %d: %v", c.min, c.max, c.exact, err)
+			continue
+		}
+		var got []rule
+		for _, n := range named {
+			got = append(got, n.rule)
+		}
+		if !slices.Equal(got, c.want) {
+			t.Errorf("-min %d -max %d -exact %d: got %+v, want %+v", c.min, c.max, c.exact, got, c.want)
+		}
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	parsetest.Generated(t, inputDecoder, "main", "parseInput", "parse_input.go")
+}
+
+// TestReaders checks that the generated parser reads the same ranges as the
+// decoder it came from, and fails in the same way on broken inputs.
+func TestReaders(t *testing.T) {
+	parsetest.Readers[[]Range]{
+		Got:    func(in []byte) ([]Range, error) { return read(bytes.NewReader(in)) },
+		Want:   func(in []byte) ([]Range, error) { return readWith(bytes.NewReader(in), inputDecoder) },
+		Breaks: []byte{'x', '\n', ',', '-', '9', ' '},
+		Extra: func(input []byte) [][]byte {
+			return [][]byte{append([]byte("1-99999999999999999999,"), input...)}
+		},
+	}.Test(t, "inputs/example.txt", "inputs/input.txt")
+}
+
+func TestInputSyntax(t *testing.T) {
+	parsetest.RoundTrip(t, inputSyntax, func(in []byte) ([]Range, error) { return read(bytes.NewReader(in)) },
+		"inputs/example.txt", "inputs/input.txt")
+}
+
+func BenchmarkRead(b *testing.B) {
+	raw, err := os.ReadFile("inputs/input.txt")
+	if err != nil {
+		b.Skip(err)
+	}
+	for _, c := range []struct {
+		name string
+		d    *parse.Decoder[input]
+	}{
+		{"decoder", inputDecoder},
+		{"generated", generatedInputDecoder},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			b.SetBytes(int64(len(raw)))
+			for b.Loop() {
+				if _, err := readWith(bytes.NewReader(raw), c.d); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
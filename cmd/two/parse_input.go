@@ -0,0 +1,58 @@
+// Code generated by parse.Decoder.Generate. DO NOT EDIT.
+
+package main
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+// parseInput decodes input values with the format "{ranges}".
+func parseInput(_ *parse.State, input []byte) (parse.ParseResult[input], error) {
+	return parse.DecodeWith(input, parseInput_3)
+}
+
+// Range with the format "{a}-{b}"
+func parseInput_1(input []byte, v *Range) ([]byte, error) {
+	var err error
+	if input, err = parse.DecodeUint(input, &v.A, "uint64"); err != nil {
+		return nil, parse.NewFieldError("a", err)
+	}
+	if input, err = parse.DecodeLiteral(input, "-"); err != nil {
+		return nil, err
+	}
+	if input, err = parse.DecodeUint(input, &v.B, "uint64"); err != nil {
+		return nil, parse.NewFieldError("b", err)
+	}
+	return input, nil
+}
+
+// []Range separated by ","
+func parseInput_2(input []byte, v *[]Range) ([]byte, error) {
+	s := slices.Grow(*v, 1)
+	for i := 0; ; i++ {
+		s = slices.Grow(s, 1)[:i+1]
+		rest, err := parseInput_1(input, &s[i])
+		if err != nil {
+			return nil, parse.NewFieldError("["+strconv.Itoa(i)+"]", err)
+		}
+		input = rest
+		if len(input) < 1 || string(input[:1]) != "," {
+			break
+		}
+		input = input[1:]
+	}
+	*v = s
+	return input, nil
+}
+
+// input with the format "{ranges}"
+func parseInput_3(input []byte, v *input) ([]byte, error) {
+	var err error
+	if input, err = parseInput_2(input, &v.Ranges); err != nil {
+		return nil, parse.NewFieldError("ranges", err)
+	}
+	return input, nil
+}
@@ -0,0 +1,34 @@
+// Package grammars has a grammar for each day's input, written in the
+// notation described on parse.Grammar, along with parsers generated from
+// them.
+package grammars
+
+import (
+	"embed"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+//go:generate go run ../cmd/aoc generate -grammar @one.grammar -func ParseOne
+//go:generate go run ../cmd/aoc generate -grammar @two.grammar -func ParseTwo
+//go:generate go run ../cmd/aoc generate -grammar @three.grammar -func ParseThree
+//go:generate go run ../cmd/aoc generate -grammar @four.grammar -func ParseFour
+//go:generate go run ../cmd/aoc generate -grammar @five.grammar -func ParseFive
+//go:generate go run ../cmd/aoc generate -grammar @six.grammar -func ParseSix
+//go:generate go run ../cmd/aoc generate -grammar @seven.grammar -func ParseSeven
+//go:generate go run ../cmd/aoc generate -grammar @eight.grammar -func ParseEight
+//go:generate go run ../cmd/aoc generate -grammar @nine.grammar -func ParseNine
+//go:generate go run ../cmd/aoc generate -grammar @ten.grammar -func ParseTen
+//go:generate go run ../cmd/aoc generate -grammar @eleven.grammar -func ParseEleven
+
+//go:embed *.grammar
+var files embed.FS
+
+// Grammar returns the grammar for a day, by its name ("one", "two" and so on).
+func Grammar(day string) (*parse.Grammar, error) {
+	src, err := files.ReadFile(day + ".grammar")
+	if err != nil {
+		return nil, err
+	}
+	return parse.ParseGrammar(string(src))
+}
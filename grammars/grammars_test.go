@@ -0,0 +1,94 @@
+package grammars
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+var days = []struct {
+	name      string
+	input     string
+	generated func([]byte) (*parse.Node, error)
+}{
+	{"one", "../cmd/one/inputs/input.txt", ParseOne},
+	{"two", "../cmd/two/inputs/input.txt", ParseTwo},
+	{"three", "../cmd/three/inputs/input.txt", ParseThree},
+	{"four", "../cmd/four/input/input.txt", ParseFour},
+	{"five", "../cmd/five/inputs/input.txt", ParseFive},
+	{"six", "../cmd/six/inputs/input.txt", ParseSix},
+	{"seven", "../cmd/seven/inputs/input.txt", ParseSeven},
+	{"eight", "../cmd/eight/inputs/input.txt", ParseEight},
+	{"nine", "../cmd/nine/inputs/inputs.txt", ParseNine},
+	{"ten", "../cmd/ten/inputs/input.txt", ParseTen},
+	{"eleven", "../cmd/eleven/inputs/input.txt", ParseEleven},
+}
+
+func TestGenerated(t *testing.T) {
+	for _, day := range days {
+		t.Run(day.name, func(t *testing.T) {
+			g, err := Grammar(day.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input, err := os.ReadFile(day.input)
+			if err != nil {
+				t.Skip(err)
+			}
+			compare := func(input []byte) {
+				t.Helper()
+				want, wantErr := g.Parse(input)
+				got, gotErr := day.generated(input)
+				if fmt.Sprint(gotErr) != fmt.Sprint(wantErr) {
+					t.Fatalf("got error %v, want %v", gotErr, wantErr)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatal("generated parser made a different tree")
+				}
+			}
+			compare(input)
+
+			// And the errors should be the same too, wherever things
+			// go wrong.
+			for _, at := range []int{0, 1, len(input) / 3, len(input) / 2, len(input) - 2} {
+				for _, b := range []byte{'x', '\n', ' ', '9', ',', '-'} {
+					broken := append(input[:at:at], b)
+					compare(append(broken, input[at:]...))
+				}
+				compare(input[:at])
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, day := range days {
+		g, err := Grammar(day.name)
+		if err != nil {
+			b.Fatal(err)
+		}
+		input, err := os.ReadFile(day.input)
+		if err != nil {
+			continue
+		}
+		b.Run(day.name+"/interpreted", func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for b.Loop() {
+				if _, err := g.Parse(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(day.name+"/generated", func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for b.Loop() {
+				if _, err := day.generated(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// ParseEight parses input with the grammar starting at boxes.
+func ParseEight(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseEight_boxes(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In boxes: box % nl
+func parseEight_boxes_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseEight_box(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// boxes = box % nl
+func parseEight_boxes(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseEight_boxes_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("boxes", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "boxes"
+	}
+	return n, end, nil
+}
+
+// In box: x:uint
+func parseEight_box_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("x", n, pos, end), end, nil
+}
+
+// In box: y:uint
+func parseEight_box_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("y", n, pos, end), end, nil
+}
+
+// In box: z:uint
+func parseEight_box_4(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("z", n, pos, end), end, nil
+}
+
+// In box: x:uint "," y:uint "," z:uint
+func parseEight_box_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseEight_box_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, ",", "\",\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseEight_box_3(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, ",", "\",\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseEight_box_4(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// box = x:uint "," y:uint "," z:uint
+func parseEight_box(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseEight_box_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("box", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "box"
+	}
+	return n, end, nil
+}
@@ -0,0 +1,160 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// ParseEleven parses input with the grammar starting at devices.
+func ParseEleven(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseEleven_devices(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In devices: device % nl
+func parseEleven_devices_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseEleven_device(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// devices = device % nl
+func parseEleven_devices(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseEleven_devices_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("devices", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "devices"
+	}
+	return n, end, nil
+}
+
+// In device: name:word
+func parseEleven_device_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Word(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("name", n, pos, end), end, nil
+}
+
+// In device: ws word
+func parseEleven_device_5(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := p.Spaces(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Word(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// In device: (ws word)+
+func parseEleven_device_4(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	count := 0
+	for {
+		n, end, err := parseEleven_device_5(p, pos)
+		if err != nil || end == pos {
+			break
+		}
+		count++
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	if count == 0 {
+		return nil, start, p.NoneFound()
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In device: outputs:(ws word)+
+func parseEleven_device_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseEleven_device_4(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("outputs", n, pos, end), end, nil
+}
+
+// In device: name:word ":" outputs:(ws word)+
+func parseEleven_device_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseEleven_device_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, ":", "\":\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseEleven_device_3(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// device = name:word ":" outputs:(ws word)+
+func parseEleven_device(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseEleven_device_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("device", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "device"
+	}
+	return n, end, nil
+}
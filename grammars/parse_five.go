@@ -0,0 +1,200 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// ParseFive parses input with the grammar starting at database.
+func ParseFive(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseFive_database(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In database: range % nl
+func parseFive_database_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseFive_range(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In database: fresh:range % nl
+func parseFive_database_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseFive_database_3(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("fresh", n, pos, end), end, nil
+}
+
+// In database: uint % nl
+func parseFive_database_5(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := p.Uint(next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In database: ids:uint % nl
+func parseFive_database_4(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseFive_database_5(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("ids", n, pos, end), end, nil
+}
+
+// In database: fresh:range % nl nl nl ids:uint % nl
+func parseFive_database_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseFive_database_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Newline(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Newline(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseFive_database_4(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// database = fresh:range % nl nl nl ids:uint % nl
+func parseFive_database(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseFive_database_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("database", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "database"
+	}
+	return n, end, nil
+}
+
+// In range: start:uint
+func parseFive_range_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("start", n, pos, end), end, nil
+}
+
+// In range: end:uint
+func parseFive_range_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("end", n, pos, end), end, nil
+}
+
+// In range: start:uint "-" end:uint
+func parseFive_range_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseFive_range_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, "-", "\"-\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseFive_range_3(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// range = start:uint "-" end:uint
+func parseFive_range(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseFive_range_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("range", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "range"
+	}
+	return n, end, nil
+}
@@ -0,0 +1,72 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"regexp"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+var (
+	parseFour_re0 = regexp.MustCompile("^(?:[.@]+)")
+)
+
+// ParseFour parses input with the grammar starting at grid.
+func ParseFour(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseFour_grid(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In grid: row % nl
+func parseFour_grid_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseFour_row(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// grid = row % nl
+func parseFour_grid(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseFour_grid_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("grid", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "grid"
+	}
+	return n, end, nil
+}
+
+// row = /[.@]+/
+func parseFour_row(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Regexp(pos, parseFour_re0, "/[.@]+/")
+	if err != nil {
+		return nil, pos, p.Label("row", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "row"
+	}
+	return n, end, nil
+}
@@ -0,0 +1,115 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// ParseNine parses input with the grammar starting at tiles.
+func ParseNine(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseNine_tiles(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In tiles: tile % nl
+func parseNine_tiles_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseNine_tile(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// tiles = tile % nl
+func parseNine_tiles(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseNine_tiles_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("tiles", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "tiles"
+	}
+	return n, end, nil
+}
+
+// In tile: x:uint
+func parseNine_tile_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("x", n, pos, end), end, nil
+}
+
+// In tile: y:uint
+func parseNine_tile_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("y", n, pos, end), end, nil
+}
+
+// In tile: x:uint "," y:uint
+func parseNine_tile_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseNine_tile_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, ",", "\",\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseNine_tile_3(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// tile = x:uint "," y:uint
+func parseNine_tile(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseNine_tile_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("tile", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "tile"
+	}
+	return n, end, nil
+}
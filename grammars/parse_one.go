@@ -0,0 +1,115 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// ParseOne parses input with the grammar starting at turns.
+func ParseOne(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseOne_turns(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In turns: turn % nl
+func parseOne_turns_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseOne_turn(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// turns = turn % nl
+func parseOne_turns(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseOne_turns_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("turns", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "turns"
+	}
+	return n, end, nil
+}
+
+// In turn: "L" | "R"
+func parseOne_turn_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	if n, end, err := p.Literal(pos, "L", "\"L\""); err == nil {
+		return n, end, nil
+	}
+	return p.Literal(pos, "R", "\"R\"")
+}
+
+// In turn: dir:("L" | "R")
+func parseOne_turn_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseOne_turn_3(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("dir", n, pos, end), end, nil
+}
+
+// In turn: steps:uint
+func parseOne_turn_4(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("steps", n, pos, end), end, nil
+}
+
+// In turn: dir:("L" | "R") steps:uint
+func parseOne_turn_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseOne_turn_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseOne_turn_4(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// turn = dir:("L" | "R") steps:uint
+func parseOne_turn(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseOne_turn_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("turn", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "turn"
+	}
+	return n, end, nil
+}
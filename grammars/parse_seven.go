@@ -0,0 +1,72 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"regexp"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+var (
+	parseSeven_re0 = regexp.MustCompile("^(?:[.S^]+)")
+)
+
+// ParseSeven parses input with the grammar starting at manifold.
+func ParseSeven(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseSeven_manifold(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In manifold: row % nl
+func parseSeven_manifold_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseSeven_row(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// manifold = row % nl
+func parseSeven_manifold(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseSeven_manifold_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("manifold", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "manifold"
+	}
+	return n, end, nil
+}
+
+// row = /[.S^]+/
+func parseSeven_row(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Regexp(pos, parseSeven_re0, "/[.S^]+/")
+	if err != nil {
+		return nil, pos, p.Label("row", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "row"
+	}
+	return n, end, nil
+}
@@ -0,0 +1,239 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"regexp"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+var (
+	parseSix_re0 = regexp.MustCompile("^(?:[*+])")
+)
+
+// ParseSix parses input with the grammar starting at worksheet.
+func ParseSix(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseSix_worksheet(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In worksheet: row % nl
+func parseSix_worksheet_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseSix_row(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In worksheet: numbers:row % nl
+func parseSix_worksheet_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseSix_worksheet_3(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("numbers", n, pos, end), end, nil
+}
+
+// In worksheet: /[*+]/ % ws
+func parseSix_worksheet_6(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Spaces(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := p.Regexp(next, parseSix_re0, "/[*+]/")
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In worksheet: ws /[*+]/ % ws ws
+func parseSix_worksheet_5(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := p.Spaces(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseSix_worksheet_6(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Spaces(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// In worksheet: ops:(ws /[*+]/ % ws ws)
+func parseSix_worksheet_4(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseSix_worksheet_5(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("ops", n, pos, end), end, nil
+}
+
+// In worksheet: numbers:row % nl nl ops:(ws /[*+]/ % ws ws)
+func parseSix_worksheet_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseSix_worksheet_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Newline(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseSix_worksheet_4(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// worksheet = numbers:row % nl nl ops:(ws /[*+]/ % ws ws)
+func parseSix_worksheet(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseSix_worksheet_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("worksheet", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "worksheet"
+	}
+	return n, end, nil
+}
+
+// In row: uint % ws
+func parseSix_row_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Spaces(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := p.Uint(next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In row: ws uint % ws ws
+func parseSix_row_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := p.Spaces(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseSix_row_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Spaces(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// row = ws uint % ws ws
+func parseSix_row(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseSix_row_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("row", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "row"
+	}
+	return n, end, nil
+}
@@ -0,0 +1,329 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"regexp"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+var (
+	parseTen_re0 = regexp.MustCompile("^(?:[.#]+)")
+)
+
+// ParseTen parses input with the grammar starting at machines.
+func ParseTen(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseTen_machines(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In machines: machine % nl
+func parseTen_machines_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseTen_machine(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// machines = machine % nl
+func parseTen_machines(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseTen_machines_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("machines", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "machines"
+	}
+	return n, end, nil
+}
+
+// In machine: lights:/[.#]+/
+func parseTen_machine_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Regexp(pos, parseTen_re0, "/[.#]+/")
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("lights", n, pos, end), end, nil
+}
+
+// In machine: ws button
+func parseTen_machine_5(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := p.Spaces(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseTen_button(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// In machine: (ws button)+
+func parseTen_machine_4(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	count := 0
+	for {
+		n, end, err := parseTen_machine_5(p, pos)
+		if err != nil || end == pos {
+			break
+		}
+		count++
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	if count == 0 {
+		return nil, start, p.NoneFound()
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In machine: buttons:(ws button)+
+func parseTen_machine_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseTen_machine_4(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("buttons", n, pos, end), end, nil
+}
+
+// In machine: uint % ","
+func parseTen_machine_8(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Literal(pos, ",", "\",\"")
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := p.Uint(next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In machine: "{" uint % "," "}"
+func parseTen_machine_7(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := p.Literal(pos, "{", "\"{\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseTen_machine_8(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, "}", "\"}\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// In machine: joltages:("{" uint % "," "}")
+func parseTen_machine_6(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseTen_machine_7(p, pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("joltages", n, pos, end), end, nil
+}
+
+// In machine: "[" lights:/[.#]+/ "]" buttons:(ws button)+ ws joltages:("{" uint % "," "}")
+func parseTen_machine_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := p.Literal(pos, "[", "\"[\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseTen_machine_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, "]", "\"]\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseTen_machine_3(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Spaces(pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseTen_machine_6(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// machine = "[" lights:/[.#]+/ "]" buttons:(ws button)+ ws joltages:("{" uint % "," "}")
+func parseTen_machine(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseTen_machine_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("machine", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "machine"
+	}
+	return n, end, nil
+}
+
+// In button: uint % ","
+func parseTen_button_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Literal(pos, ",", "\",\"")
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := p.Uint(next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// In button: "(" uint % "," ")"
+func parseTen_button_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := p.Literal(pos, "(", "\"(\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseTen_button_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, ")", "\")\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// button = "(" uint % "," ")"
+func parseTen_button(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseTen_button_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("button", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "button"
+	}
+	return n, end, nil
+}
@@ -0,0 +1,72 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"regexp"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+var (
+	parseThree_re0 = regexp.MustCompile("^(?:[0-9]+)")
+)
+
+// ParseThree parses input with the grammar starting at banks.
+func ParseThree(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseThree_banks(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In banks: bank % nl
+func parseThree_banks_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Newline(pos)
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseThree_bank(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// banks = bank % nl
+func parseThree_banks(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseThree_banks_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("banks", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "banks"
+	}
+	return n, end, nil
+}
+
+// bank = /[0-9]+/
+func parseThree_bank(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Regexp(pos, parseThree_re0, "/[0-9]+/")
+	if err != nil {
+		return nil, pos, p.Label("bank", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "bank"
+	}
+	return n, end, nil
+}
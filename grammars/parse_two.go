@@ -0,0 +1,115 @@
+// Code generated by aoc generate. DO NOT EDIT.
+
+package grammars
+
+import (
+	"github.com/pfcm/aoc25/parse"
+)
+
+// ParseTwo parses input with the grammar starting at ranges.
+func ParseTwo(input []byte) (*parse.Node, error) {
+	p := parse.NewGenerated(input)
+	n, end, err := parseTwo_ranges(p, 0)
+	return p.Finish(n, end, err)
+}
+
+// In ranges: range % ","
+func parseTwo_ranges_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var items []*parse.Node
+	for first := true; ; first = false {
+		next := pos
+		if !first {
+			_, end, err := p.Literal(pos, ",", "\",\"")
+			if err != nil {
+				break
+			}
+			next = end
+		}
+		n, end, err := parseTwo_range(p, next)
+		if err != nil {
+			if first {
+				return nil, start, err
+			}
+			break
+		}
+		if n != nil {
+			items = append(items, n)
+		}
+		pos = end
+	}
+	return p.List(items, start, pos), pos, nil
+}
+
+// ranges = range % ","
+func parseTwo_ranges(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseTwo_ranges_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("ranges", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "ranges"
+	}
+	return n, end, nil
+}
+
+// In range: start:uint
+func parseTwo_range_2(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("start", n, pos, end), end, nil
+}
+
+// In range: end:uint
+func parseTwo_range_3(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := p.Uint(pos)
+	if err != nil {
+		return nil, pos, err
+	}
+	return p.Capture("end", n, pos, end), end, nil
+}
+
+// In range: start:uint "-" end:uint
+func parseTwo_range_1(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	start := pos
+	var kept []*parse.Node
+	n, end, err := parseTwo_range_2(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = p.Literal(pos, "-", "\"-\"")
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	n, end, err = parseTwo_range_3(p, pos)
+	if err != nil {
+		return nil, start, err
+	}
+	if n != nil {
+		kept = append(kept, n)
+	}
+	pos = end
+	return p.Seq(kept, start, pos), pos, nil
+}
+
+// range = start:uint "-" end:uint
+func parseTwo_range(p *parse.Generated, pos int) (*parse.Node, int, error) {
+	n, end, err := parseTwo_range_1(p, pos)
+	if err != nil {
+		return nil, pos, p.Label("range", err)
+	}
+	if n != nil && n.Name == "" {
+		n.Name = "range"
+	}
+	return n, end, nil
+}
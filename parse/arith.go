@@ -0,0 +1,195 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrOverflow is returned when evaluating an expression overflows.
+	ErrOverflow = errors.New("integer overflow")
+	// ErrDivideByZero is returned when evaluating an expression divides by
+	// zero.
+	ErrDivideByZero = errors.New("division by zero")
+)
+
+// Arith is an integer arithmetic expression. It's either a number, when Op is
+// empty, or an operator applied to one or two arguments.
+type Arith struct {
+	Op    string
+	Value int
+	Args  []*Arith
+}
+
+// Arithmetic parses integer arithmetic expressions with the usual
+// precedence: from loosest to tightest, left associative + and -, left
+// associative *, / and %, right associative ^ and then unary - and +.
+var Arithmetic = Expression(
+	Apply(Int[int], func(i int) *Arith { return &Arith{Value: i} }),
+	[]Level[*Arith]{{
+		Assoc: AssocLeft,
+		Infix: binaryOps("+", "-"),
+	}, {
+		Assoc: AssocLeft,
+		Infix: binaryOps("*", "/", "%"),
+	}, {
+		Assoc: AssocRight,
+		Infix: binaryOps("^"),
+	}, {
+		Prefix: map[string]func(*Arith) *Arith{
+			"-": func(a *Arith) *Arith { return &Arith{Op: "-", Args: []*Arith{a}} },
+			"+": func(a *Arith) *Arith { return a },
+		},
+	}},
+)
+
+func binaryOps(ops ...string) map[string]func(a, b *Arith) *Arith {
+	m := make(map[string]func(a, b *Arith) *Arith)
+	for _, op := range ops {
+		m[op] = func(a, b *Arith) *Arith { return &Arith{Op: op, Args: []*Arith{a, b}} }
+	}
+	return m
+}
+
+// Eval parses and evaluates a whole arithmetic expression.
+func Eval(s string) (int, error) {
+	r, err := Arithmetic(nil, []byte(s))
+	if err != nil {
+		return 0, err
+	}
+	if rest := strings.TrimSpace(string(r.remainder)); rest != "" {
+		_, err := unexpectedError("end of expression", r.remainder)
+		return 0, err
+	}
+	return r.result.Eval()
+}
+
+// Eval evaluates the expression, returning an error if it overflows or
+// divides by zero.
+func (a *Arith) Eval() (int, error) {
+	if a.Op == "" {
+		return a.Value, nil
+	}
+	args := make([]int, len(a.Args))
+	for i, arg := range a.Args {
+		v, err := arg.Eval()
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	if len(args) == 1 {
+		if a.Op != "-" {
+			return 0, fmt.Errorf("unknown unary operator %q", a.Op)
+		}
+		if args[0] == math.MinInt {
+			return 0, ErrOverflow
+		}
+		return -args[0], nil
+	}
+	x, y := args[0], args[1]
+	switch a.Op {
+	case "+":
+		return Add(x, y)
+	case "-":
+		return Sub(x, y)
+	case "*":
+		return Mul(x, y)
+	case "/":
+		return Div(x, y)
+	case "%":
+		// It fits, but only because the quotient doesn't.
+		if _, err := Div(x, y); err != nil {
+			return 0, err
+		}
+		return x % y, nil
+	case "^":
+		if y < 0 {
+			return 0, fmt.Errorf("negative exponent %d", y)
+		}
+		return Pow(x, y)
+	}
+	return 0, fmt.Errorf("unknown operator %q", a.Op)
+}
+
+// Add adds two integers, returning ErrOverflow if the result doesn't fit.
+func Add(x, y int) (int, error) {
+	s := x + y
+	if (y > 0 && s < x) || (y < 0 && s > x) {
+		return 0, ErrOverflow
+	}
+	return s, nil
+}
+
+// Sub subtracts y from x, returning ErrOverflow if the result doesn't fit.
+func Sub(x, y int) (int, error) {
+	if y == math.MinInt {
+		if x >= 0 {
+			return 0, ErrOverflow
+		}
+		return x - y, nil
+	}
+	return Add(x, -y)
+}
+
+// Mul multiplies two integers, returning ErrOverflow if the result doesn't
+// fit.
+func Mul(x, y int) (int, error) {
+	if x == 0 || y == 0 {
+		return 0, nil
+	}
+	p := x * y
+	if p/y != x || (x == -1 && y == math.MinInt) || (y == -1 && x == math.MinInt) {
+		return 0, ErrOverflow
+	}
+	return p, nil
+}
+
+// Div divides x by y, rounding towards zero like Go's /. It returns
+// ErrDivideByZero if y is zero and ErrOverflow for math.MinInt / -1.
+func Div(x, y int) (int, error) {
+	switch {
+	case y == 0:
+		return 0, ErrDivideByZero
+	case x == math.MinInt && y == -1:
+		return 0, ErrOverflow
+	}
+	return x / y, nil
+}
+
+// Pow raises x to the power y, which must not be negative, returning
+// ErrOverflow if the result doesn't fit. It squares rather than multiplying y
+// times, so huge exponents of 0, 1 and -1 are fine.
+func Pow(x, y int) (int, error) {
+	result := 1
+	for {
+		var err error
+		if y&1 == 1 {
+			if result, err = Mul(result, x); err != nil {
+				return 0, err
+			}
+		}
+		if y >>= 1; y == 0 {
+			return result, nil
+		}
+		// Every bit left needs at least this much, so if it overflows
+		// so would the result.
+		if x, err = Mul(x, x); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// String returns the expression fully parenthesised.
+func (a *Arith) String() string {
+	switch len(a.Args) {
+	case 0:
+		return strconv.Itoa(a.Value)
+	case 1:
+		return a.Op + a.Args[0].String()
+	}
+	return "(" + a.Args[0].String() + " " + a.Op + " " + a.Args[1].String() + ")"
+}
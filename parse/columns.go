@@ -0,0 +1,172 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Block is a rectangular piece of column aligned text.
+type Block struct {
+	Start int      // the column of the left edge of the block
+	Rows  [][]byte // all padded with spaces to the same width
+}
+
+// Reading is an order to read the text in a Block.
+type Reading uint8
+
+const (
+	// ByRows reads each row left to right, from the top row down.
+	ByRows Reading = iota
+	// ByColumns reads each column top to bottom, from the leftmost column
+	// rightwards.
+	ByColumns
+	// ByColumnsRightToLeft reads each column top to bottom, from the
+	// rightmost column leftwards.
+	ByColumnsRightToLeft
+)
+
+// SplitLines splits raw input into lines, without a trailing empty line if
+// the input ends with a newline.
+func SplitLines(raw []byte) [][]byte {
+	lines := bytes.Split(raw, []byte{'\n'})
+	if n := len(lines) - 1; len(lines[n]) == 0 {
+		lines = lines[:n]
+	}
+	return lines
+}
+
+// Blocks splits lines of column aligned text into blocks, wherever there's a
+// column that is blank on every line. The lines don't have to be the same
+// length: anything past the end of a line counts as blank, so it doesn't
+// matter if trailing spaces have been trimmed.
+func Blocks(lines [][]byte) []Block {
+	width := 0
+	for _, l := range lines {
+		width = max(width, len(l))
+	}
+	blank := func(col int) bool {
+		for _, l := range lines {
+			if col < len(l) && l[col] != ' ' {
+				return false
+			}
+		}
+		return true
+	}
+	var blocks []Block
+	for col := 0; col < width; {
+		if blank(col) {
+			col++
+			continue
+		}
+		start := col
+		for col < width && !blank(col) {
+			col++
+		}
+		rows := make([][]byte, len(lines))
+		for i, l := range lines {
+			rows[i] = pad(l, start, col)
+		}
+		blocks = append(blocks, Block{Start: start, Rows: rows})
+	}
+	return blocks
+}
+
+// BlocksSyntax reads the rest of the input as lines of column aligned text,
+// split into Blocks as Blocks does, and prints Blocks back out as lines. The
+// blocks must be in order, with the same number of rows each all as wide as
+// each other, and with at least one column between them. None of them can
+// have a blank column, because it would split in two when read back. Every
+// line is printed out to the end of the last block, so input with trailing
+// spaces trimmed reads the same but doesn't print the same.
+func BlocksSyntax() Syntax[[]Block] {
+	return Syntax[[]Block]{
+		Parser: func(_ *State, input []byte) (ParseResult[[]Block], error) {
+			return ParseResult[[]Block]{
+				result:    Blocks(SplitLines(input)),
+				remainder: input[len(input):],
+			}, nil
+		},
+		Print: printBlocks,
+	}
+}
+
+func printBlocks(dst []byte, blocks []Block) ([]byte, error) {
+	end := -1 // of the last block
+	for i, b := range blocks {
+		if b.Start <= end {
+			return nil, fmt.Errorf("block %d at column %d touches the one before it", i, b.Start)
+		}
+		if len(b.Rows) != len(blocks[0].Rows) {
+			return nil, fmt.Errorf("block %d has %d rows, not %d", i, len(b.Rows), len(blocks[0].Rows))
+		}
+		if b.Width() == 0 {
+			return nil, fmt.Errorf("block %d is empty", i)
+		}
+		for _, row := range b.Rows {
+			if len(row) != b.Width() {
+				return nil, fmt.Errorf("block %d has rows of different widths", i)
+			}
+			if bytes.IndexByte(row, '\n') >= 0 {
+				return nil, fmt.Errorf("block %d has a newline in it", i)
+			}
+		}
+		for c, col := range b.Read(ByColumns) {
+			if len(bytes.Trim(col, " ")) == 0 {
+				return nil, fmt.Errorf("block %d has a blank column %d", i, c)
+			}
+		}
+		end = b.Start + b.Width()
+	}
+	if len(blocks) == 0 {
+		return dst, nil
+	}
+	for r := range blocks[0].Rows {
+		line := len(dst)
+		for _, b := range blocks {
+			for len(dst)-line < b.Start {
+				dst = append(dst, ' ')
+			}
+			dst = append(dst, b.Rows[r]...)
+		}
+		dst = append(dst, '\n')
+	}
+	return dst, nil
+}
+
+// pad returns l[start:end], padded with spaces if l is too short.
+func pad(l []byte, start, end int) []byte {
+	row := bytes.Repeat([]byte{' '}, end-start)
+	if start < len(l) {
+		copy(row, l[start:min(end, len(l))])
+	}
+	return row
+}
+
+// Width is the number of columns in the block.
+func (b Block) Width() int {
+	if len(b.Rows) == 0 {
+		return 0
+	}
+	return len(b.Rows[0])
+}
+
+// Read returns the text in the block in the given order: the rows for ByRows,
+// otherwise the columns each read from top to bottom.
+func (b Block) Read(r Reading) [][]byte {
+	if r == ByRows {
+		return b.Rows
+	}
+	cols := make([][]byte, b.Width())
+	for c := range cols {
+		col := make([]byte, len(b.Rows))
+		for i, row := range b.Rows {
+			col[i] = row[c]
+		}
+		if r == ByColumnsRightToLeft {
+			cols[len(cols)-1-c] = col
+		} else {
+			cols[c] = col
+		}
+	}
+	return cols
+}
@@ -0,0 +1,77 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlocks(t *testing.T) {
+	// Trailing spaces trimmed, so the lines are all different lengths.
+	lines := SplitLines([]byte("123 328  51\n 45 64  387\n  6 98\n*   +   *\n"))
+	blocks := Blocks(lines)
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3", len(blocks))
+	}
+	if b := blocks[2]; b.Start != 8 || b.Width() != 3 {
+		t.Errorf("last block: got start %d, width %d; want 8, 3", b.Start, b.Width())
+	}
+	for _, c := range []struct {
+		reading Reading
+		want    []string
+	}{
+		{ByRows, []string{" 51", "387", "   ", "*  "}},
+		{ByColumns, []string{" 3 *", "58  ", "17  "}},
+		{ByColumnsRightToLeft, []string{"17  ", "58  ", " 3 *"}},
+	} {
+		var got []string
+		for _, b := range blocks[2].Read(c.reading) {
+			got = append(got, string(b))
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Read(%d): got %q, want %q", c.reading, got, c.want)
+		}
+	}
+}
+
+func TestBlocksSyntax(t *testing.T) {
+	input := "123 328  51 64 \n 45 64  387 23 \n  6 98  215 314\n*   +   *   +  \n"
+	s := BlocksSyntax()
+	blocks, err := Run(SeqL(s.Parser, End), []byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 4 {
+		t.Fatalf("got %d blocks, want 4", len(blocks))
+	}
+	got, err := s.Bytes(blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != input {
+		t.Errorf("printing: got %q, want %q", got, input)
+	}
+
+	row := func(rows ...string) [][]byte {
+		var b [][]byte
+		for _, r := range rows {
+			b = append(b, []byte(r))
+		}
+		return b
+	}
+	for _, c := range []struct {
+		name   string
+		blocks []Block
+	}{
+		{"touching", []Block{{Start: 0, Rows: row("1")}, {Start: 1, Rows: row("2")}}},
+		{"out of order", []Block{{Start: 2, Rows: row("1")}, {Start: 0, Rows: row("2")}}},
+		{"different heights", []Block{{Start: 0, Rows: row("1")}, {Start: 2, Rows: row("2", "3")}}},
+		{"ragged", []Block{{Start: 0, Rows: row("12", "3")}}},
+		{"empty", []Block{{Start: 0, Rows: row("")}}},
+		{"blank column", []Block{{Start: 0, Rows: row("1 2", "3 4")}}},
+		{"newline", []Block{{Start: 0, Rows: row("1\n2")}}},
+	} {
+		if b, err := s.Bytes(c.blocks); err == nil {
+			t.Errorf("%s: printed %q, want an error", c.name, b)
+		}
+	}
+}
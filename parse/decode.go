@@ -0,0 +1,82 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Decoder fills in structs of type T from whole lines of input, according to
+// a format such as "{start}-{end}". See Format for the details.
+type Decoder[T any] struct {
+	p      Parser[T]
+	format string
+	// generated is set for Decoders made by DecoderOf, which don't have a
+	// format.
+	generated bool
+}
+
+// FieldError is the error returned when a particular field could not be
+// decoded.
+type FieldError struct {
+	Field string // the path to the field, eg. "from.x" or "buttons[2]"
+	Err   error
+}
+
+func (e *FieldError) Error() string { return fmt.Sprintf("field %s: %v", e.Field, e.Err) }
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// NewFieldError wraps err in a FieldError for the named field, prepending to
+// the path if err already is one.
+func NewFieldError(field string, err error) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		sep := "."
+		if strings.HasPrefix(fe.Field, "[") {
+			sep = ""
+		}
+		return &FieldError{Field: field + sep + fe.Field, Err: fe.Err}
+	}
+	return &FieldError{Field: field, Err: err}
+}
+
+// NewDecoder compiles a Decoder for T. An empty format decodes all the fields
+// of T separated by commas.
+func NewDecoder[T any](format string) (*Decoder[T], error) {
+	p, err := CompileFormat[T](format)
+	if err != nil {
+		return nil, err
+	}
+	return &Decoder[T]{p: p, format: format}, nil
+}
+
+// DecoderOf returns a Decoder that uses p, which is usually a function
+// written by Generate.
+func DecoderOf[T any](p Parser[T]) *Decoder[T] {
+	return &Decoder[T]{p: p, generated: true}
+}
+
+// MustDecoder is like NewDecoder, but panics if the format is invalid.
+func MustDecoder[T any](format string) *Decoder[T] {
+	d, err := NewDecoder[T](format)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Decode decodes a whole line.
+func (d *Decoder[T]) Decode(line []byte) (T, error) {
+	result, err := d.p(nil, line)
+	if err == nil && len(result.remainder) > 0 {
+		_, err = unexpectedError("end of line", result.remainder)
+	}
+	if err != nil {
+		var t T
+		return t, err
+	}
+	return result.result, nil
+}
+
+// Parser returns a Parser that decodes a T from the front of its input.
+func (d *Decoder[T]) Parser() Parser[T] { return d.p }
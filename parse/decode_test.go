@@ -0,0 +1,75 @@
+package parse
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type decodeRange struct {
+	Start, End uint64
+}
+
+type decodePoint struct {
+	X, Y int
+}
+
+type decodeLine struct {
+	Name   string
+	From   decodePoint `format:"{x},{y}"`
+	To     decodePoint
+	Values [][]uint8 `sep:" " sep1:","`
+}
+
+func TestDecoder(t *testing.T) {
+	r, err := MustDecoder[decodeRange]("{start}-{end}").Decode([]byte("12-345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (decodeRange{12, 345}); r != want {
+		t.Errorf("got %v, want %v", r, want)
+	}
+
+	l, err := MustDecoder[decodeLine]("{name}: {from} -> {to} = {values}").
+		Decode([]byte("a line: 1,-2 -> -3,4 = 1,2 3 4,5,6"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := decodeLine{
+		Name:   "a line",
+		From:   decodePoint{1, -2},
+		To:     decodePoint{-3, 4},
+		Values: [][]uint8{{1, 2}, {3}, {4, 5, 6}},
+	}
+	if !reflect.DeepEqual(l, want) {
+		t.Errorf("got %+v, want %+v", l, want)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	d := MustDecoder[decodeLine]("{name}: {from} -> {to} = {values}")
+	for _, c := range []struct {
+		input string
+		field string
+	}{{
+		input: "a: x,1 -> 1,1 = 1",
+		field: "from.x",
+	}, {
+		input: "a: 1,1 -> 1,1 = 1 2,256",
+		field: "values[1][1]",
+	}} {
+		_, err := d.Decode([]byte(c.input))
+		var fe *FieldError
+		if !errors.As(err, &fe) {
+			t.Errorf("Decode(%q): got error %v, want a FieldError", c.input, err)
+			continue
+		}
+		if fe.Field != c.field {
+			t.Errorf("Decode(%q): got error for field %q, want %q", c.input, fe.Field, c.field)
+		}
+	}
+
+	if _, err := NewDecoder[decodeRange]("{start}-{nope}"); err == nil {
+		t.Error("NewDecoder with an unknown capture: no error")
+	}
+}
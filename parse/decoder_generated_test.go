@@ -0,0 +1,207 @@
+// Code generated by parse.Decoder.Generate. DO NOT EDIT.
+
+package parse_test
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+// decodeRecord decodes genRecord values with the format "{name}: {points} {ranges} {grid} #{count} | {words}".
+func decodeRecord(_ *parse.State, input []byte) (parse.ParseResult[genRecord], error) {
+	return parse.DecodeWith(input, decodeRecord_8)
+}
+
+// genPoint with its fields separated by commas
+func decodeRecord_1(input []byte, v *genPoint) ([]byte, error) {
+	var err error
+	if input, err = parse.DecodeInt(input, &v.X, "int8"); err != nil {
+		return nil, parse.NewFieldError("X", err)
+	}
+	if input, err = parse.DecodeLiteral(input, ","); err != nil {
+		return nil, err
+	}
+	if input, err = parse.DecodeInt(input, &v.Y, "int8"); err != nil {
+		return nil, parse.NewFieldError("Y", err)
+	}
+	return input, nil
+}
+
+// []genPoint separated by ";" in []
+func decodeRecord_2(input []byte, v *[]genPoint) ([]byte, error) {
+	s := slices.Grow(*v, 1)
+	var err error
+	if input, err = parse.DecodeLiteral(input, "["); err != nil {
+		return nil, err
+	}
+	for i := 0; ; i++ {
+		if i == 0 && len(input) > 0 && input[0] == ']' {
+			// Empty list.
+			break
+		}
+		s = slices.Grow(s, 1)[:i+1]
+		rest, err := decodeRecord_1(input, &s[i])
+		if err != nil {
+			return nil, parse.NewFieldError("["+strconv.Itoa(i)+"]", err)
+		}
+		input = rest
+		if len(input) < 1 || string(input[:1]) != ";" {
+			break
+		}
+		input = input[1:]
+	}
+	if input, err = parse.DecodeLiteral(input, "]"); err != nil {
+		return nil, err
+	}
+	*v = s
+	return input, nil
+}
+
+// genRange with the format "{lo}-{hi}"
+func decodeRecord_3(input []byte, v *genRange) ([]byte, error) {
+	var err error
+	if input, err = parse.DecodeUint(input, &v.Lo, "uint16"); err != nil {
+		return nil, parse.NewFieldError("lo", err)
+	}
+	if input, err = parse.DecodeLiteral(input, "-"); err != nil {
+		return nil, err
+	}
+	if input, err = parse.DecodeUint(input, &v.Hi, "uint16"); err != nil {
+		return nil, parse.NewFieldError("hi", err)
+	}
+	return input, nil
+}
+
+// []genRange separated by ","
+func decodeRecord_4(input []byte, v *[]genRange) ([]byte, error) {
+	s := slices.Grow(*v, 1)
+	for i := 0; ; i++ {
+		s = slices.Grow(s, 1)[:i+1]
+		rest, err := decodeRecord_3(input, &s[i])
+		if err != nil {
+			return nil, parse.NewFieldError("["+strconv.Itoa(i)+"]", err)
+		}
+		input = rest
+		if len(input) < 1 || string(input[:1]) != "," {
+			break
+		}
+		input = input[1:]
+	}
+	*v = s
+	return input, nil
+}
+
+// []uint8 separated by "," in ()
+func decodeRecord_5(input []byte, v *[]uint8) ([]byte, error) {
+	s := slices.Grow(*v, 1)
+	var err error
+	if input, err = parse.DecodeLiteral(input, "("); err != nil {
+		return nil, err
+	}
+	for i := 0; ; i++ {
+		if i == 0 && len(input) > 0 && input[0] == ')' {
+			// Empty list.
+			break
+		}
+		s = slices.Grow(s, 1)[:i+1]
+		rest, err := parse.DecodeUint(input, &s[i], "uint8")
+		if err != nil {
+			return nil, parse.NewFieldError("["+strconv.Itoa(i)+"]", err)
+		}
+		input = rest
+		if len(input) < 1 || string(input[:1]) != "," {
+			break
+		}
+		input = input[1:]
+	}
+	if input, err = parse.DecodeLiteral(input, ")"); err != nil {
+		return nil, err
+	}
+	*v = s
+	return input, nil
+}
+
+// [][]uint8 separated by " "
+func decodeRecord_6(input []byte, v *[][]uint8) ([]byte, error) {
+	s := slices.Grow(*v, 1)
+	var beforeSep []byte
+	for i := 0; ; i++ {
+		s = slices.Grow(s, 1)[:i+1]
+		rest, err := decodeRecord_5(input, &s[i])
+		if err != nil {
+			if i > 0 {
+				// Put the separator back, it wasn't ours.
+				input, s = beforeSep, s[:i]
+				break
+			}
+			return nil, parse.NewFieldError("["+strconv.Itoa(i)+"]", err)
+		}
+		input = rest
+		if len(input) < 1 || string(input[:1]) != " " {
+			break
+		}
+		beforeSep, input = input, input[1:]
+	}
+	*v = s
+	return input, nil
+}
+
+// []string separated by " "
+func decodeRecord_7(input []byte, v *[]string) ([]byte, error) {
+	s := slices.Grow(*v, 1)
+	for i := 0; ; i++ {
+		s = slices.Grow(s, 1)[:i+1]
+		rest, err := parse.DecodeString(input, &s[i], " ")
+		if err != nil {
+			return nil, parse.NewFieldError("["+strconv.Itoa(i)+"]", err)
+		}
+		input = rest
+		if len(input) < 1 || string(input[:1]) != " " {
+			break
+		}
+		input = input[1:]
+	}
+	*v = s
+	return input, nil
+}
+
+// genRecord with the format "{name}: {points} {ranges} {grid} #{count} | {words}"
+func decodeRecord_8(input []byte, v *genRecord) ([]byte, error) {
+	var err error
+	if input, err = parse.DecodeString(input, &v.Name, ":"); err != nil {
+		return nil, parse.NewFieldError("name", err)
+	}
+	if input, err = parse.DecodeLiteral(input, ": "); err != nil {
+		return nil, err
+	}
+	if input, err = decodeRecord_2(input, &v.Points); err != nil {
+		return nil, parse.NewFieldError("points", err)
+	}
+	if input, err = parse.DecodeLiteral(input, " "); err != nil {
+		return nil, err
+	}
+	if input, err = decodeRecord_4(input, &v.Ranges); err != nil {
+		return nil, parse.NewFieldError("ranges", err)
+	}
+	if input, err = parse.DecodeLiteral(input, " "); err != nil {
+		return nil, err
+	}
+	if input, err = decodeRecord_6(input, &v.Grid); err != nil {
+		return nil, parse.NewFieldError("grid", err)
+	}
+	if input, err = parse.DecodeLiteral(input, " #"); err != nil {
+		return nil, err
+	}
+	if input, err = parse.DecodeInt(input, &v.Count, "int"); err != nil {
+		return nil, parse.NewFieldError("count", err)
+	}
+	if input, err = parse.DecodeLiteral(input, " | "); err != nil {
+		return nil, err
+	}
+	if input, err = decodeRecord_7(input, &v.Words); err != nil {
+		return nil, parse.NewFieldError("words", err)
+	}
+	return input, nil
+}
@@ -0,0 +1,168 @@
+package parse
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// ChainL1 returns a parser for one or more p separated by op, combining the
+// results of p with the functions op returns from left to right. This is the
+// usual way to parse left associative operators, so ChainL1(Int, minus) on
+// "1-2-3" gives (1-2)-3.
+func ChainL1[In Input, A any](p ParserOf[In, A], op ParserOf[In, func(A, A) A]) ParserOf[In, A] {
+	return func(s *State, input In) (ResultOf[In, A], error) {
+		r, err := p(s, input)
+		if err != nil {
+			return ResultOf[In, A]{}, err
+		}
+		acc := r.result
+		input = r.remainder
+		for {
+			o, err := op(s, input)
+			if fatal(err) {
+				return ResultOf[In, A]{}, err
+			}
+			if err != nil {
+				break
+			}
+			rhs, err := p(s, o.remainder)
+			if err != nil {
+				return ResultOf[In, A]{}, err
+			}
+			acc = o.result(acc, rhs.result)
+			input = rhs.remainder
+		}
+		return ResultOf[In, A]{
+			result:    acc,
+			remainder: input,
+		}, nil
+	}
+}
+
+// ChainR1 is like ChainL1, but combines the results from right to left, for
+// right associative operators: ChainR1(Int, pow) on "2^3^2" gives 2^(3^2).
+func ChainR1[In Input, A any](p ParserOf[In, A], op ParserOf[In, func(A, A) A]) ParserOf[In, A] {
+	return func(s *State, input In) (ResultOf[In, A], error) {
+		r, err := p(s, input)
+		if err != nil {
+			return ResultOf[In, A]{}, err
+		}
+		var (
+			operands = []A{r.result}
+			ops      []func(A, A) A
+		)
+		input = r.remainder
+		for {
+			o, err := op(s, input)
+			if fatal(err) {
+				return ResultOf[In, A]{}, err
+			}
+			if err != nil {
+				break
+			}
+			rhs, err := p(s, o.remainder)
+			if err != nil {
+				return ResultOf[In, A]{}, err
+			}
+			ops = append(ops, o.result)
+			operands = append(operands, rhs.result)
+			input = rhs.remainder
+		}
+		acc := operands[len(operands)-1]
+		for i := len(ops) - 1; i >= 0; i-- {
+			acc = ops[i](operands[i], acc)
+		}
+		return ResultOf[In, A]{
+			result:    acc,
+			remainder: input,
+		}, nil
+	}
+}
+
+// Prefix returns a parser for p preceded by any number of prefix operators,
+// which are applied innermost first: Prefix(neg, Int) on "--1" gives -(-1).
+func Prefix[In Input, A any](op ParserOf[In, func(A) A], p ParserOf[In, A]) ParserOf[In, A] {
+	return Apply(Seq(Many(op), p), func(pair Pair[[]func(A) A, A]) A {
+		a := pair.Second
+		for i := len(pair.First) - 1; i >= 0; i-- {
+			a = pair.First[i](a)
+		}
+		return a
+	})
+}
+
+// Postfix returns a parser for p followed by any number of postfix
+// operators, which are applied from left to right.
+func Postfix[In Input, A any](p ParserOf[In, A], op ParserOf[In, func(A) A]) ParserOf[In, A] {
+	return Apply(Seq(p, Many(op)), func(pair Pair[A, []func(A) A]) A {
+		a := pair.First
+		for _, f := range pair.Second {
+			a = f(a)
+		}
+		return a
+	})
+}
+
+// Assoc is the associativity of the infix operators in a Level.
+type Assoc uint8
+
+const (
+	AssocLeft Assoc = iota
+	AssocRight
+)
+
+// Level is a set of operators that all bind equally tightly.
+type Level[A any] struct {
+	Assoc   Assoc
+	Infix   map[string]func(A, A) A
+	Prefix  map[string]func(A) A
+	Postfix map[string]func(A) A
+}
+
+// Expression returns a parser for expressions made up of atoms combined with
+// the operators in table, which is ordered from the loosest binding level to
+// the tightest. Any expression can be put in parentheses to group it, and
+// spaces and tabs are allowed between tokens.
+func Expression[In Input, A any](atom ParserOf[In, A], table []Level[A]) ParserOf[In, A] {
+	expr := NewRefOf[In, A]()
+	group := Between(lexeme(ByteOf[In]('(')), expr.Parser(), lexeme(ByteOf[In](')')))
+	p := Or(lexeme(atom), group)
+	for _, l := range slices.Backward(table) {
+		if len(l.Prefix) > 0 {
+			p = Prefix(operators[In](l.Prefix), p)
+		}
+		if len(l.Postfix) > 0 {
+			p = Postfix(p, operators[In](l.Postfix))
+		}
+		if len(l.Infix) > 0 {
+			switch l.Assoc {
+			case AssocLeft:
+				p = ChainL1(p, operators[In](l.Infix))
+			case AssocRight:
+				p = ChainR1(p, operators[In](l.Infix))
+			}
+		}
+	}
+	expr.Set(p)
+	return expr.Parser()
+}
+
+// lexeme returns a parser that skips leading whitespace before running p.
+func lexeme[In Input, A any](p ParserOf[In, A]) ParserOf[In, A] {
+	return SeqR(SpacesOf[In], p)
+}
+
+// operators returns a parser for any of the tokens in ops, trying the longest
+// first so that eg. "**" isn't mistaken for "*".
+func operators[In Input, F any](ops map[string]F) ParserOf[In, F] {
+	tokens := slices.SortedFunc(maps.Keys(ops), func(a, b string) int {
+		return cmp.Or(len(b)-len(a), cmp.Compare(a, b))
+	})
+	ps := make([]ParserOf[In, F], len(tokens))
+	for i, t := range tokens {
+		f := ops[t]
+		ps[i] = lexeme(Apply(LiteralOf[In](t), func(string) F { return f }))
+	}
+	return Or(ps...)
+}
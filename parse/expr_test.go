@@ -0,0 +1,85 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	for _, c := range []struct {
+		expr   string
+		want   int
+		parsed string
+	}{
+		{expr: "1 + 2 * 3", want: 7, parsed: "(1 + (2 * 3))"},
+		{expr: "(1 + 2) * 3", want: 9, parsed: "((1 + 2) * 3)"},
+		{expr: "10 - 4 - 3", want: 3, parsed: "((10 - 4) - 3)"},
+		{expr: "2 ^ 3 ^ 2", want: 512, parsed: "(2 ^ (3 ^ 2))"},
+		{expr: "-2 ^ 2", want: 4, parsed: "(-2 ^ 2)"},
+		{expr: "--3*-(1+1)", want: -6, parsed: "(--3 * -(1 + 1))"},
+		{expr: "7 % 4 / 2", want: 1, parsed: "((7 % 4) / 2)"},
+		{expr: "1^9223372036854775807", want: 1, parsed: "(1 ^ 9223372036854775807)"},
+		{expr: "0^9223372036854775807", want: 0, parsed: "(0 ^ 9223372036854775807)"},
+		{expr: "-1^9223372036854775807", want: -1, parsed: "(-1 ^ 9223372036854775807)"},
+		{expr: "-1^9223372036854775806", want: 1, parsed: "(-1 ^ 9223372036854775806)"},
+		{expr: "2^62", want: 1 << 62, parsed: "(2 ^ 62)"},
+		{expr: "-2^63", want: -1 << 63, parsed: "(-2 ^ 63)"},
+		{expr: "3^0", want: 1, parsed: "(3 ^ 0)"},
+		{expr: "-7 / 2", want: -3, parsed: "(-7 / 2)"},
+	} {
+		a, err := Run(Arithmetic, []byte(c.expr))
+		if err != nil {
+			t.Errorf("Run(Arithmetic, %q): %v", c.expr, err)
+			continue
+		}
+		if got := a.String(); got != c.parsed {
+			t.Errorf("Run(Arithmetic, %q) = %s, want %s", c.expr, got, c.parsed)
+		}
+		got, err := Eval(c.expr)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", c.expr, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Eval(%q) = %d, want %d", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	for _, c := range []struct {
+		expr string
+		want error
+	}{
+		{expr: "1 / (2 - 2)", want: ErrDivideByZero},
+		{expr: "9223372036854775807 + 1", want: ErrOverflow},
+		{expr: "-9223372036854775807 - 2", want: ErrOverflow},
+		{expr: "(-9223372036854775807 - 1) / -1", want: ErrOverflow},
+		{expr: "(-9223372036854775807 - 1) % -1", want: ErrOverflow},
+		{expr: "2 ^ 64", want: ErrOverflow},
+		{expr: "2 ^ 63", want: ErrOverflow},
+		{expr: "2 ^ 9223372036854775807", want: ErrOverflow},
+		{expr: "(1 + 2", want: nil},
+		{expr: "1 + 2 )", want: nil},
+	} {
+		_, err := Eval(c.expr)
+		if err == nil {
+			t.Errorf("Eval(%q): no error", c.expr)
+			continue
+		}
+		if c.want != nil && !errors.Is(err, c.want) {
+			t.Errorf("Eval(%q): got error %v, want %v", c.expr, err, c.want)
+		}
+	}
+}
+
+func TestExpressionString(t *testing.T) {
+	p := Expression(IntOf[int, string], []Level[int]{
+		{Infix: map[string]func(a, b int) int{"+": func(a, b int) int { return a + b }}},
+		{Infix: map[string]func(a, b int) int{"*": func(a, b int) int { return a * b }}},
+		{Prefix: map[string]func(a int) int{"-": func(a int) int { return -a }}},
+	})
+	if got, err := Run(SeqL(p, EndOf[string]), " 2 * (3 + -4) + 1"); err != nil || got != -1 {
+		t.Errorf("got %d, %v; want -1", got, err)
+	}
+}
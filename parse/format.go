@@ -0,0 +1,449 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Format returns a parser for values of type T that is compiled from a
+// format such as "{start}-{end}" or "[{lights}] {buttons} {joltages}". It
+// panics if the format is invalid, see CompileFormat for a version that
+// doesn't.
+//
+// Each {name} in the format is a capture that is decoded into the field of T
+// with that name, and everything else must appear literally in the input.
+// Literal braces are written "{{" and "}}". An empty format is all of the
+// fields of T separated by commas.
+//
+// Fields are matched to captures by their `parse:"name"` tag if they have
+// one, otherwise by their name (ignoring case). Only exported fields are
+// decoded, and the rest are left out of the default format, as if they were
+// tagged `parse:"-"`. Fields can be signed or unsigned integers, strings,
+// slices or nested structs:
+//   - strings capture everything up to the next literal in the format.
+//   - slices are elements separated by the `sep:","` tag (a comma by default),
+//     optionally surrounded by the pair of bytes in the `brackets:"()"` tag.
+//     Nested slices use `sep1`, `brackets1`, `sep2` and so on.
+//   - nested structs are decoded with the format in their `format` tag, or
+//     their fields separated by commas if there isn't one.
+//
+// Errors from decoding a particular field are returned as a *FieldError.
+func Format[T any](format string) Parser[T] {
+	p, err := CompileFormat[T](format)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// CompileFormat is like Format, but returns an error if the format is invalid.
+func CompileFormat[T any](format string) (Parser[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only decode structs, not %v", t)
+	}
+	decode, err := compileStruct(t, format, "")
+	if err != nil {
+		return nil, err
+	}
+	return func(s *State, input []byte) (ParseResult[T], error) {
+		var result T
+		rest, err := decode(input, reflect.ValueOf(&result).Elem())
+		if err != nil {
+			return ParseResult[T]{}, err
+		}
+		return ParseResult[T]{
+			result:    result,
+			remainder: rest,
+		}, nil
+	}, nil
+}
+
+// decodeFunc decodes the front of the input into v, returning what's left.
+type decodeFunc func(input []byte, v reflect.Value) ([]byte, error)
+
+// segment is a piece of a format: either a literal or a capture.
+type segment struct {
+	literal string
+	capture string
+}
+
+func splitFormat(format string) ([]segment, error) {
+	var (
+		segments []segment
+		literal  strings.Builder
+	)
+	for i := 0; i < len(format); i++ {
+		switch c := format[i]; {
+		case strings.HasPrefix(format[i:], "{{"), strings.HasPrefix(format[i:], "}}"):
+			literal.WriteByte(c)
+			i++
+		case c == '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed capture in format %q", format)
+			}
+			if literal.Len() > 0 {
+				segments = append(segments, segment{literal: literal.String()})
+				literal.Reset()
+			}
+			name := format[i+1 : i+end]
+			if name == "" {
+				return nil, fmt.Errorf("empty capture in format %q", format)
+			}
+			segments = append(segments, segment{capture: name})
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("unmatched } in format %q", format)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	if literal.Len() > 0 {
+		segments = append(segments, segment{literal: literal.String()})
+	}
+	return segments, nil
+}
+
+// fieldName is the name a field is captured with, or "-" if it isn't.
+func fieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return "-"
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("parse"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// structSegments returns the pieces of the format for a struct type, using the
+// default format if it's empty.
+func structSegments(t reflect.Type, format string) ([]segment, error) {
+	if format == "" {
+		var names []string
+		for i := range t.NumField() {
+			if f := t.Field(i); fieldName(f) != "-" {
+				names = append(names, "{"+fieldName(f)+"}")
+			}
+		}
+		format = strings.Join(names, ",")
+	}
+	return splitFormat(format)
+}
+
+// captureField finds the field of t for the capture in segments[i], and works
+// out which bytes end it: the start of the following literal if there is one,
+// otherwise whatever ends the whole struct.
+func captureField(t reflect.Type, segments []segment, i int, stops string) (reflect.StructField, string, error) {
+	name := segments[i].capture
+	for j := range t.NumField() {
+		f := t.Field(j)
+		if n := fieldName(f); n == "-" || !strings.EqualFold(n, name) {
+			continue
+		}
+		if i+1 < len(segments) && segments[i+1].literal != "" {
+			stops = segments[i+1].literal[:1]
+		}
+		return f, stops, nil
+	}
+	for j := range t.NumField() {
+		if f := t.Field(j); !f.IsExported() && strings.EqualFold(f.Name, name) {
+			return reflect.StructField{}, "", fmt.Errorf("field %s of %v for capture {%s} isn't exported", f.Name, t, name)
+		}
+	}
+	return reflect.StructField{}, "", fmt.Errorf("%v has no field for capture {%s}", t, name)
+}
+
+// compileStruct compiles a decodeFunc for a struct type. stops are the bytes
+// that end a string at the end of the format.
+func compileStruct(t reflect.Type, format, stops string) (decodeFunc, error) {
+	segments, err := structSegments(t, format)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		decoders []decodeFunc
+		seen     = make(map[int]bool)
+	)
+	for i, seg := range segments {
+		if seg.capture == "" {
+			lit := seg.literal
+			decoders = append(decoders, func(input []byte, _ reflect.Value) ([]byte, error) {
+				return DecodeLiteral(input, lit)
+			})
+			continue
+		}
+		field, fieldStops, err := captureField(t, segments, i, stops)
+		if err != nil {
+			return nil, err
+		}
+		if seen[field.Index[0]] {
+			return nil, fmt.Errorf("capture {%s} appears twice", seg.capture)
+		}
+		seen[field.Index[0]] = true
+		decode, err := compileValue(field.Type, field.Tag, 0, fieldStops)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		var (
+			index = field.Index[0]
+			name  = seg.capture
+		)
+		decoders = append(decoders, func(input []byte, v reflect.Value) ([]byte, error) {
+			rest, err := decode(input, v.Field(index))
+			if err != nil {
+				return nil, NewFieldError(name, err)
+			}
+			return rest, nil
+		})
+	}
+	return func(input []byte, v reflect.Value) ([]byte, error) {
+		var err error
+		for _, d := range decoders {
+			input, err = d(input, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return input, nil
+	}, nil
+}
+
+// levelTag looks up a tag for a given level of slice nesting: "sep" for the
+// outermost, "sep1" for the next and so on.
+func levelTag(tag reflect.StructTag, key string, level int) (string, bool) {
+	if level > 0 {
+		key += strconv.Itoa(level)
+	}
+	return tag.Lookup(key)
+}
+
+// compileValue compiles a decodeFunc for any supported type.
+func compileValue(t reflect.Type, tag reflect.StructTag, level int, stops string) (decodeFunc, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return func(input []byte, v reflect.Value) ([]byte, error) {
+			str, rest := decodeString(input, stops)
+			v.SetString(str)
+			return rest, nil
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits, name := t.Bits(), t.String()
+		return func(input []byte, v reflect.Value) ([]byte, error) {
+			n, rest, err := decodeInt(input, bits, name)
+			if err != nil {
+				return nil, err
+			}
+			v.SetInt(n)
+			return rest, nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bits, name := t.Bits(), t.String()
+		return func(input []byte, v reflect.Value) ([]byte, error) {
+			n, rest, err := decodeUint(input, bits, name)
+			if err != nil {
+				return nil, err
+			}
+			v.SetUint(n)
+			return rest, nil
+		}, nil
+	case reflect.Struct:
+		return compileStruct(t, tag.Get("format"), stops)
+	case reflect.Slice:
+		return compileSlice(t, tag, level, stops)
+	}
+	return nil, fmt.Errorf("can't decode %v", t)
+}
+
+// listLayout is how a slice is laid out in the input.
+type listLayout struct {
+	sep              string
+	opening, closing []byte // nil without brackets
+	elemStops        string
+	// If the separator could also be the start of whatever comes after
+	// the list, then we can't tell a bad element from the end of the
+	// list.
+	ambiguous bool
+}
+
+func sliceLayout(tag reflect.StructTag, level int, stops string) (listLayout, error) {
+	sep, ok := levelTag(tag, "sep", level)
+	if !ok {
+		sep = ","
+	}
+	if sep == "" {
+		return listLayout{}, errors.New("empty separator")
+	}
+	var opening, closing []byte
+	if brackets, ok := levelTag(tag, "brackets", level); ok {
+		if len(brackets) != 2 {
+			return listLayout{}, fmt.Errorf("brackets must be a pair of bytes, not %q", brackets)
+		}
+		opening, closing = []byte{brackets[0]}, []byte{brackets[1]}
+		// Everything inside the brackets is ours.
+		stops = ""
+	}
+	return listLayout{
+		sep:       sep,
+		opening:   opening,
+		closing:   closing,
+		elemStops: stops + sep[:1] + string(closing),
+		ambiguous: strings.IndexByte(stops, sep[0]) != -1,
+	}, nil
+}
+
+func compileSlice(t reflect.Type, tag reflect.StructTag, level int, stops string) (decodeFunc, error) {
+	layout, err := sliceLayout(tag, level, stops)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		sep              = layout.sep
+		opening, closing = layout.opening, layout.closing
+		ambiguous        = layout.ambiguous
+	)
+	elem, err := compileValue(t.Elem(), tag, level+1, layout.elemStops)
+	if err != nil {
+		return nil, err
+	}
+	return func(input []byte, v reflect.Value) ([]byte, error) {
+		var (
+			s         = reflect.MakeSlice(t, 0, 1)
+			beforeSep []byte
+		)
+		if opening != nil {
+			var err error
+			if input, err = DecodeLiteral(input, string(opening)); err != nil {
+				return nil, err
+			}
+		}
+		for i := 0; ; i++ {
+			if i == 0 && closing != nil && bytes.HasPrefix(input, closing) {
+				// Empty list.
+				break
+			}
+			e := reflect.New(t.Elem()).Elem()
+			rest, err := elem(input, e)
+			if err != nil {
+				if i > 0 && ambiguous {
+					// Put the separator back, it wasn't
+					// ours.
+					input = beforeSep
+					break
+				}
+				return nil, NewFieldError("["+strconv.Itoa(i)+"]", err)
+			}
+			s = reflect.Append(s, e)
+			input = rest
+			if len(input) < len(sep) || string(input[:len(sep)]) != sep {
+				break
+			}
+			beforeSep, input = input, input[len(sep):]
+		}
+		if closing != nil {
+			var err error
+			if input, err = DecodeLiteral(input, string(closing)); err != nil {
+				return nil, err
+			}
+		}
+		v.Set(s)
+		return input, nil
+	}, nil
+}
+
+// The Decode functions read the pieces of a format from the front of the
+// input. They're shared by Format and the code from Decoder.Generate, so that
+// both go wrong in exactly the same ways.
+
+// DecodeWith decodes a T with decode.
+func DecodeWith[T any](input []byte, decode func([]byte, *T) ([]byte, error)) (ParseResult[T], error) {
+	var v T
+	rest, err := decode(input, &v)
+	if err != nil {
+		return ParseResult[T]{}, err
+	}
+	return ParseResult[T]{
+		result:    v,
+		remainder: rest,
+	}, nil
+}
+
+// DecodeLiteral matches lit.
+func DecodeLiteral(input []byte, lit string) ([]byte, error) {
+	if len(input) < len(lit) && string(input) == lit[:len(input)] {
+		_, err := endedEarly(lit, input)
+		return nil, err
+	}
+	if len(input) < len(lit) || string(input[:len(lit)]) != lit {
+		_, err := unexpectedError(lit, input)
+		return nil, err
+	}
+	return input[len(lit):], nil
+}
+
+// DecodeString captures everything up to the first of the bytes in stops. It
+// never fails.
+func DecodeString[T ~string](input []byte, v *T, stops string) ([]byte, error) {
+	str, rest := decodeString(input, stops)
+	*v = T(str)
+	return rest, nil
+}
+
+// DecodeInt reads an integer that has to fit in a T, name is how the type is
+// described if it doesn't.
+func DecodeInt[T constraints.Signed](input []byte, v *T, name string) ([]byte, error) {
+	n, rest, err := decodeInt(input, int(unsafe.Sizeof(*v))*8, name)
+	if err != nil {
+		return nil, err
+	}
+	*v = T(n)
+	return rest, nil
+}
+
+// DecodeUint is DecodeInt for unsigned integers.
+func DecodeUint[T constraints.Unsigned](input []byte, v *T, name string) ([]byte, error) {
+	n, rest, err := decodeUint(input, int(unsafe.Sizeof(*v))*8, name)
+	if err != nil {
+		return nil, err
+	}
+	*v = T(n)
+	return rest, nil
+}
+
+func decodeString(input []byte, stops string) (string, []byte) {
+	end := 0
+	for end < len(input) && strings.IndexByte(stops, input[end]) == -1 {
+		end++
+	}
+	return string(input[:end]), input[end:]
+}
+
+func decodeInt(input []byte, bits int, name string) (int64, []byte, error) {
+	r, err := Int[int64](nil, input)
+	if err != nil {
+		return 0, nil, err
+	}
+	if shift := 64 - bits; r.result<<shift>>shift != r.result {
+		return 0, nil, fmt.Errorf("%d overflows %s", r.result, name)
+	}
+	return r.result, r.remainder, nil
+}
+
+func decodeUint(input []byte, bits int, name string) (uint64, []byte, error) {
+	r, err := Uint[uint64](nil, input)
+	if err != nil {
+		return 0, nil, err
+	}
+	if shift := 64 - bits; r.result<<shift>>shift != r.result {
+		return 0, nil, fmt.Errorf("%d overflows %s", r.result, name)
+	}
+	return r.result, r.remainder, nil
+}
@@ -0,0 +1,72 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+type formatMachine struct {
+	Lights   string
+	Buttons  [][]int `sep:" " brackets1:"()"`
+	Joltages []int   `brackets:"{}"`
+}
+
+func TestFormat(t *testing.T) {
+	p := Format[formatMachine]("[{lights}] {buttons} {joltages}")
+	for _, c := range []struct {
+		input string
+		want  formatMachine
+	}{{
+		input: "[.##.] (3) (1,3) (2) {3,5,4,7}",
+		want: formatMachine{
+			Lights:   ".##.",
+			Buttons:  [][]int{{3}, {1, 3}, {2}},
+			Joltages: []int{3, 5, 4, 7},
+		},
+	}, {
+		input: "[] () {}",
+		want: formatMachine{
+			Lights:   "",
+			Buttons:  [][]int{{}},
+			Joltages: []int{},
+		},
+	}} {
+		got, err := Run(p, []byte(c.input))
+		if err != nil {
+			t.Errorf("Run(%q): %v", c.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Run(%q): got %+v, want %+v", c.input, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{
+		"[.##.",
+		"[.##.] (3",
+		"[.##.] (3) {3,x}",
+	} {
+		if _, err := Run(p, []byte(bad)); err == nil {
+			t.Errorf("Run(%q): no error", bad)
+		}
+	}
+}
+
+func TestFormatUnexported(t *testing.T) {
+	type partly struct {
+		A    int
+		b    int
+		C, d string
+	}
+	if _, err := CompileFormat[partly]("{a}-{b}"); err == nil {
+		t.Error("capture of an unexported field: no error")
+	}
+	// The default format leaves them out.
+	got, err := Run(Format[partly](""), []byte("1,x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (partly{A: 1, C: "x"}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,458 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Generate writes Go source for a function in package pkg called name, with
+// the signature
+//
+//	func name(input []byte) (*parse.Node, error)
+//
+// which parses input exactly like g.Parse does, with the same tree and the
+// same errors, but without any closures: each rule and each piece of a rule
+// is a plain function. Left recursive grammars, which g's own parser can only
+// report at runtime, are an error.
+//
+// It works from the Grammar rather than from parsers built out of the
+// combinators, because those are Go functions that can't be turned back into
+// source. Decoder.Generate does the same for formats, with typed results.
+func (g *Grammar) Generate(w io.Writer, pkg, name string) error {
+	if err := g.checkLeftRecursion(); err != nil {
+		return err
+	}
+	gen := &generator{
+		prefix: strings.ToLower(name[:1]) + name[1:],
+	}
+	for _, r := range g.Rules {
+		gen.rule(r)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by aoc generate. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	src.WriteString("import (\n")
+	if len(gen.regexps) > 0 {
+		src.WriteString("\t\"regexp\"\n\n")
+	}
+	src.WriteString("\t\"github.com/pfcm/aoc25/parse\"\n)\n\n")
+	if len(gen.regexps) > 0 {
+		src.WriteString("var (\n")
+		for i, re := range gen.regexps {
+			fmt.Fprintf(&src, "\t%s_re%d = regexp.MustCompile(%q)\n", gen.prefix, i, "^(?:"+re+")")
+		}
+		src.WriteString(")\n\n")
+	}
+	fmt.Fprintf(&src, "// %s parses input with the grammar starting at %s.\n", name, g.Rules[0].Name)
+	fmt.Fprintf(&src, "func %s(input []byte) (*parse.Node, error) {\n", name)
+	src.WriteString("\tp := parse.NewGenerated(input)\n")
+	fmt.Fprintf(&src, "\tn, end, err := %s(p, 0)\n", gen.ruleFunc(g.Rules[0].Name))
+	src.WriteString("\treturn p.Finish(n, end, err)\n}\n")
+	src.Write(gen.funcs.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated invalid code: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// checkLeftRecursion returns an error if any rule can get back to itself
+// without consuming any input.
+func (g *Grammar) checkLeftRecursion() error {
+	rules := make(map[string]*Clause, len(g.Rules))
+	for _, r := range g.Rules {
+		rules[r.Name] = r.Clause
+	}
+
+	// First work out which rules can match without consuming anything,
+	// by iterating until nothing changes.
+	empty := make(map[string]bool)
+	var nullable func(c *Clause) bool
+	nullable = func(c *Clause) bool {
+		switch c.Kind {
+		case ClauseLiteral:
+			return c.Text == ""
+		case ClauseRegexp:
+			return regexp.MustCompile(`^(?:` + c.Text + `)`).MatchString("")
+		case ClauseBuiltin:
+			return c.Text == "ws" || c.Text == "rest" || c.Text == "end"
+		case ClauseRule:
+			return empty[c.Text]
+		case ClauseSeq:
+			for _, a := range c.Args {
+				if !nullable(a) {
+					return false
+				}
+			}
+			return true
+		case ClauseChoice:
+			for _, a := range c.Args {
+				if nullable(a) {
+					return true
+				}
+			}
+			return false
+		case ClauseMany, ClauseOptional:
+			return true
+		}
+		return nullable(c.Args[0])
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, c := range rules {
+			if !empty[name] && nullable(c) {
+				empty[name], changed = true, true
+			}
+		}
+	}
+
+	// Then which rules each rule can call before consuming anything.
+	var first func(c *Clause, calls map[string]bool)
+	first = func(c *Clause, calls map[string]bool) {
+		switch c.Kind {
+		case ClauseRule:
+			calls[c.Text] = true
+		case ClauseSeq:
+			for _, a := range c.Args {
+				first(a, calls)
+				if !nullable(a) {
+					return
+				}
+			}
+		case ClauseSepBy:
+			first(c.Args[0], calls)
+			if nullable(c.Args[0]) {
+				first(c.Args[1], calls)
+			}
+		default:
+			for _, a := range c.Args {
+				first(a, calls)
+			}
+		}
+	}
+	for _, r := range g.Rules {
+		seen := map[string]bool{}
+		todo := []string{r.Name}
+		for len(todo) > 0 {
+			name := todo[len(todo)-1]
+			todo = todo[:len(todo)-1]
+			calls := make(map[string]bool)
+			first(rules[name], calls)
+			for call := range calls {
+				if call == r.Name {
+					return fmt.Errorf("rule %q: %w", r.Name, ErrLeftRecursion)
+				}
+				if !seen[call] {
+					seen[call] = true
+					todo = append(todo, call)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// generator writes the functions for a grammar's rules.
+type generator struct {
+	prefix  string
+	funcs   bytes.Buffer
+	regexps []string
+
+	current string // rule that's being written
+	n       int    // functions written for it so far
+}
+
+func (gen *generator) ruleFunc(rule string) string {
+	return gen.prefix + "_" + rule
+}
+
+func (gen *generator) rule(r *Rule) {
+	gen.current, gen.n = r.Name, 0
+	var body strings.Builder
+	fmt.Fprintf(&body, "n, end, err := %s\n", gen.call(r.Clause, "pos"))
+	fmt.Fprintf(&body, "if err != nil {\nreturn nil, pos, p.Label(%q, err)\n}\n", r.Name)
+	fmt.Fprintf(&body, "if n != nil && n.Name == \"\" {\nn.Name = %q\n}\n", r.Name)
+	body.WriteString("return n, end, nil\n")
+	gen.function(gen.ruleFunc(r.Name), r.Name+" = "+r.Clause.String(), body.String())
+}
+
+func (gen *generator) function(name, comment, body string) {
+	fmt.Fprintf(&gen.funcs, "\n// %s\nfunc %s(p *parse.Generated, pos int) (*parse.Node, int, error) {\n%s}\n", comment, name, body)
+}
+
+// call returns an expression that matches c at pos, writing a function for
+// it first if it needs one.
+func (gen *generator) call(c *Clause, pos string) string {
+	switch c.Kind {
+	case ClauseLiteral:
+		return fmt.Sprintf("p.Literal(%s, %q, %q)", pos, c.Text, strconv.Quote(c.Text))
+	case ClauseRegexp:
+		gen.regexps = append(gen.regexps, c.Text)
+		return fmt.Sprintf("p.Regexp(%s, %s_re%d, %q)", pos, gen.prefix, len(gen.regexps)-1, "/"+c.Text+"/")
+	case ClauseBuiltin:
+		return fmt.Sprintf("p.%s(%s)", builtinMethods[c.Text], pos)
+	case ClauseRule:
+		return fmt.Sprintf("%s(p, %s)", gen.ruleFunc(c.Text), pos)
+	}
+
+	gen.n++
+	name := fmt.Sprintf("%s_%s_%d", gen.prefix, gen.current, gen.n)
+	var body strings.Builder
+	switch c.Kind {
+	case ClauseSeq:
+		body.WriteString("start := pos\nvar kept []*parse.Node\n")
+		for i, a := range c.Args {
+			assign := "="
+			if i == 0 {
+				assign = ":="
+			}
+			fmt.Fprintf(&body, "n, end, err %s %s\nif err != nil {\nreturn nil, start, err\n}\n", assign, gen.call(a, "pos"))
+			body.WriteString("if n != nil {\nkept = append(kept, n)\n}\npos = end\n")
+		}
+		body.WriteString("return p.Seq(kept, start, pos), pos, nil\n")
+	case ClauseChoice:
+		for i, a := range c.Args {
+			if i == len(c.Args)-1 {
+				fmt.Fprintf(&body, "return %s\n", gen.call(a, "pos"))
+				break
+			}
+			fmt.Fprintf(&body, "if n, end, err := %s; err == nil {\nreturn n, end, nil\n}\n", gen.call(a, "pos"))
+		}
+	case ClauseMany, ClauseSome:
+		some := c.Kind == ClauseSome
+		body.WriteString("start := pos\nvar items []*parse.Node\n")
+		if some {
+			body.WriteString("count := 0\n")
+		}
+		fmt.Fprintf(&body, "for {\nn, end, err := %s\n", gen.call(c.Args[0], "pos"))
+		body.WriteString("if err != nil || end == pos {\nbreak\n}\n")
+		if some {
+			body.WriteString("count++\n")
+		}
+		body.WriteString("if n != nil {\nitems = append(items, n)\n}\npos = end\n}\n")
+		if some {
+			body.WriteString("if count == 0 {\nreturn nil, start, p.NoneFound()\n}\n")
+		}
+		body.WriteString("return p.List(items, start, pos), pos, nil\n")
+	case ClauseOptional:
+		fmt.Fprintf(&body, "if n, end, err := %s; err == nil {\nreturn n, end, nil\n}\n", gen.call(c.Args[0], "pos"))
+		body.WriteString("return nil, pos, nil\n")
+	case ClauseSepBy:
+		body.WriteString("start := pos\nvar items []*parse.Node\nfor first := true; ; first = false {\nnext := pos\n")
+		fmt.Fprintf(&body, "if !first {\n_, end, err := %s\nif err != nil {\nbreak\n}\nnext = end\n}\n", gen.call(c.Args[1], "pos"))
+		fmt.Fprintf(&body, "n, end, err := %s\nif err != nil {\nif first {\nreturn nil, start, err\n}\nbreak\n}\n", gen.call(c.Args[0], "next"))
+		body.WriteString("if n != nil {\nitems = append(items, n)\n}\npos = end\n}\n")
+		body.WriteString("return p.List(items, start, pos), pos, nil\n")
+	case ClauseCapture:
+		fmt.Fprintf(&body, "n, end, err := %s\nif err != nil {\nreturn nil, pos, err\n}\n", gen.call(c.Args[0], "pos"))
+		fmt.Fprintf(&body, "return p.Capture(%q, n, pos, end), end, nil\n", c.Text)
+	default:
+		panic(fmt.Sprintf("unknown clause kind %d", c.Kind))
+	}
+	gen.function(name, "In "+gen.current+": "+c.String(), body.String())
+	return fmt.Sprintf("%s(p, %s)", name, pos)
+}
+
+// builtinMethods are the methods of Generated that match each builtin.
+var builtinMethods = map[string]string{
+	"int":  "Int",
+	"uint": "Uint",
+	"hex":  "Hex",
+	"word": "Word",
+	"rest": "Rest",
+	"ws":   "Spaces",
+	"nl":   "Newline",
+	"end":  "End",
+}
+
+// Generated is the state of a parser written by Grammar.Generate. Its methods
+// match a single thing at a position in the input, and return the node for
+// it (if there is one), where the match ended and an error, just as the
+// Grammar's own parser would.
+type Generated struct {
+	input    []byte
+	furthest furthest
+}
+
+// NewGenerated starts parsing input.
+func NewGenerated(input []byte) *Generated {
+	return &Generated{input: input}
+}
+
+// fail does what expect does to the errors of the parsers it wraps.
+func (g *Generated) fail(want string, pos int, err error) (*Node, int, error) {
+	if u, ok := err.(*unexpected); ok && u.remaining == len(g.input)-pos {
+		err = &unexpected{want: want, found: u.found, remaining: u.remaining}
+	}
+	g.furthest.record(err, len(g.input)-pos)
+	return nil, pos, err
+}
+
+func (g *Generated) missing(want string, pos int) (*Node, int, error) {
+	err := &unexpected{
+		want:      want,
+		found:     string(truncate(g.input[pos:])),
+		remaining: len(g.input) - pos,
+	}
+	g.furthest.record(err, err.remaining)
+	return nil, pos, err
+}
+
+func (g *Generated) leaf(start, end int) (*Node, int, error) {
+	return &Node{Text: string(g.input[start:end])}, end, nil
+}
+
+// Literal matches lit, want is how it's described in errors.
+func (g *Generated) Literal(pos int, lit, want string) (*Node, int, error) {
+	if !bytes.HasPrefix(g.input[pos:], []byte(lit)) {
+		return g.missing(want, pos)
+	}
+	return nil, pos + len(lit), nil
+}
+
+// Regexp matches re, which must be anchored at the start.
+func (g *Generated) Regexp(pos int, re *regexp.Regexp, want string) (*Node, int, error) {
+	loc := re.FindIndex(g.input[pos:])
+	if loc == nil {
+		return g.missing(want, pos)
+	}
+	return g.leaf(pos, pos+loc[1])
+}
+
+// Int matches the int builtin.
+func (g *Generated) Int(pos int) (*Node, int, error) {
+	r, err := Int[int64](nil, g.input[pos:])
+	if err != nil {
+		return g.fail("int", pos, err)
+	}
+	return g.leaf(pos, len(g.input)-len(r.remainder))
+}
+
+// Uint matches the uint builtin.
+func (g *Generated) Uint(pos int) (*Node, int, error) {
+	r, err := Uint[uint64](nil, g.input[pos:])
+	if err != nil {
+		return g.fail("uint", pos, err)
+	}
+	return g.leaf(pos, len(g.input)-len(r.remainder))
+}
+
+// Hex matches the hex builtin.
+func (g *Generated) Hex(pos int) (*Node, int, error) {
+	r, err := Hex[uint64](nil, g.input[pos:])
+	if err != nil {
+		return g.fail("hex", pos, err)
+	}
+	return g.leaf(pos, len(g.input)-len(r.remainder))
+}
+
+// Word matches the word builtin.
+func (g *Generated) Word(pos int) (*Node, int, error) {
+	end := pos
+	for end < len(g.input) && ('a' <= g.input[end]|0x20 && g.input[end]|0x20 <= 'z') {
+		end++
+	}
+	if end == pos {
+		return g.missing("word", pos)
+	}
+	return g.leaf(pos, end)
+}
+
+// Rest matches the rest builtin.
+func (g *Generated) Rest(pos int) (*Node, int, error) {
+	end := bytes.IndexByte(g.input[pos:], '\n')
+	if end == -1 {
+		return g.leaf(pos, len(g.input))
+	}
+	return g.leaf(pos, pos+end)
+}
+
+// Spaces matches the ws builtin.
+func (g *Generated) Spaces(pos int) (*Node, int, error) {
+	for pos < len(g.input) && (g.input[pos] == ' ' || g.input[pos] == '\t') {
+		pos++
+	}
+	return nil, pos, nil
+}
+
+// Newline matches the nl builtin.
+func (g *Generated) Newline(pos int) (*Node, int, error) {
+	if pos == len(g.input) || g.input[pos] != '\n' {
+		return g.missing("nl", pos)
+	}
+	return nil, pos + 1, nil
+}
+
+// End matches the end builtin.
+func (g *Generated) End(pos int) (*Node, int, error) {
+	if pos != len(g.input) {
+		return g.missing("end", pos)
+	}
+	return nil, pos, nil
+}
+
+// Label adds the name of the rule that failed to err.
+func (g *Generated) Label(name string, err error) error {
+	return fmt.Errorf("%s: %w", name, err)
+}
+
+// NoneFound is the error for a + that didn't match anything.
+func (g *Generated) NoneFound() error {
+	return errNoneFound
+}
+
+// Seq makes the node for a sequence from the nodes it kept.
+func (g *Generated) Seq(kept []*Node, start, end int) *Node {
+	var n *Node
+	switch len(kept) {
+	case 0:
+		return nil
+	case 1:
+		n = kept[0]
+	default:
+		n = &Node{Children: kept}
+	}
+	if n.Text == "" {
+		n.Text = string(g.input[start:end])
+	}
+	return n
+}
+
+// List makes the node for a list.
+func (g *Generated) List(items []*Node, start, end int) *Node {
+	return &Node{Text: string(g.input[start:end]), Children: items}
+}
+
+// Capture names n, or makes a node for the text it matched if there isn't
+// one.
+func (g *Generated) Capture(name string, n *Node, start, end int) *Node {
+	if n == nil {
+		n = &Node{Text: string(g.input[start:end])}
+	}
+	n.Name = name
+	return n
+}
+
+// Finish checks that there's nothing but whitespace after the end, and
+// returns the tree or the best error.
+func (g *Generated) Finish(n *Node, end int, err error) (*Node, error) {
+	if err == nil {
+		for _, b := range g.input[end:] {
+			if !strings.ContainsRune("\t\n\f\r ", rune(b)) {
+				_, _, err = g.missing("end of input", end)
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, g.furthest.error(g.input, err)
+	}
+	return n, nil
+}
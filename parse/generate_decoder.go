@@ -0,0 +1,227 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Generate writes Go source for a function in package pkg called name, with
+// the signature
+//
+//	func name(s *parse.State, input []byte) (parse.ParseResult[T], error)
+//
+// which decodes the front of its input exactly like d.Parser does, with the
+// same results and the same errors, but without any reflection or closures:
+// each struct and slice in T is decoded by a plain function. The code refers
+// to T and the types of its fields by name, so they have to be predeclared or
+// defined in the package T is, and can't be unnamed structs. DecoderOf turns
+// the function back into a Decoder. The generate command in cmd/aoc runs this
+// for a Decoder in a package level variable.
+//
+// Decoders and Grammars are the only parsers that can be written out as code,
+// because they're made from descriptions rather than from Go functions.
+func (d *Decoder[T]) Generate(w io.Writer, pkg, name string) error {
+	if d.generated {
+		return errors.New("can't generate a Decoder made by DecoderOf")
+	}
+	t := reflect.TypeFor[T]()
+	gen := &decoderGenerator{
+		prefix:  strings.ToLower(name[:1]) + name[1:],
+		pkgPath: t.PkgPath(),
+		written: make(map[string]string),
+	}
+	typ, err := gen.typeName(t)
+	if err != nil {
+		return err
+	}
+	decode, err := gen.structFunc(t, d.format, "")
+	if err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by parse.Decoder.Generate. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	src.WriteString("import (\n")
+	if gen.slices {
+		src.WriteString("\t\"slices\"\n\t\"strconv\"\n\n")
+	}
+	src.WriteString("\t\"github.com/pfcm/aoc25/parse\"\n)\n\n")
+	if d.format == "" {
+		fmt.Fprintf(&src, "// %s decodes %s values with their fields separated by commas.\n", name, typ)
+	} else {
+		fmt.Fprintf(&src, "// %s decodes %s values with the format %q.\n", name, typ, d.format)
+	}
+	fmt.Fprintf(&src, "func %s(_ *parse.State, input []byte) (parse.ParseResult[%s], error) {\n", name, typ)
+	fmt.Fprintf(&src, "return parse.DecodeWith(input, %s)\n}\n", decode)
+	src.Write(gen.funcs.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated invalid code: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// decoderGenerator writes the functions for a Decoder, following what
+// compileStruct and friends do. Types are only ever named in the signatures
+// of the functions, so that they can't be hidden by the variables inside them
+// (a type called input, say).
+type decoderGenerator struct {
+	prefix  string
+	pkgPath string // where the types are
+	funcs   bytes.Buffer
+	n       int               // functions written so far
+	written map[string]string // function names by their code
+	slices  bool              // whether there are any
+}
+
+// typeName returns how t is written in the generated code.
+func (gen *decoderGenerator) typeName(t reflect.Type) (string, error) {
+	switch {
+	case t.Name() != "" && (t.PkgPath() == "" || t.PkgPath() == gen.pkgPath):
+		return t.Name(), nil
+	case t.Name() == "" && t.Kind() == reflect.Slice:
+		elem, err := gen.typeName(t.Elem())
+		return "[]" + elem, err
+	}
+	return "", fmt.Errorf("can't refer to %v in generated code", t)
+}
+
+// function writes a function that decodes into a *t, and returns its name.
+func (gen *decoderGenerator) function(t reflect.Type, comment, body string) (string, error) {
+	typ, err := gen.typeName(t)
+	if err != nil {
+		return "", err
+	}
+	code := fmt.Sprintf("(input []byte, v *%s) ([]byte, error) {\n%s}\n", typ, body)
+	if name, ok := gen.written[code]; ok {
+		return name, nil
+	}
+	gen.n++
+	name := fmt.Sprintf("%s_%d", gen.prefix, gen.n)
+	gen.written[code] = name
+	fmt.Fprintf(&gen.funcs, "\n// %s\nfunc %s%s", comment, name, code)
+	return name, nil
+}
+
+// decodeCall returns a function that gives the expression that decodes into
+// the pointer it's given.
+type decodeCall func(ptr string) string
+
+func (gen *decoderGenerator) structFunc(t reflect.Type, format, stops string) (string, error) {
+	segments, err := structSegments(t, format)
+	if err != nil {
+		return "", err
+	}
+	var body strings.Builder
+	if len(segments) > 0 {
+		body.WriteString("var err error\n")
+	}
+	for i, seg := range segments {
+		if seg.capture == "" {
+			fmt.Fprintf(&body, "if input, err = parse.DecodeLiteral(input, %q); err != nil {\nreturn nil, err\n}\n", seg.literal)
+			continue
+		}
+		field, fieldStops, err := captureField(t, segments, i, stops)
+		if err != nil {
+			return "", err
+		}
+		decode, err := gen.value(field.Type, field.Tag, 0, fieldStops)
+		if err != nil {
+			return "", fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		fmt.Fprintf(&body, "if input, err = %s; err != nil {\nreturn nil, parse.NewFieldError(%q, err)\n}\n", decode("&v."+field.Name), seg.capture)
+	}
+	body.WriteString("return input, nil\n")
+	comment := fmt.Sprintf("%v with the format %q", t.Name(), format)
+	if format == "" {
+		comment = fmt.Sprintf("%v with its fields separated by commas", t.Name())
+	}
+	return gen.function(t, comment, body.String())
+}
+
+// call is the decodeCall for a function written by the generator.
+func call(f string) decodeCall {
+	return func(ptr string) string { return fmt.Sprintf("%s(input, %s)", f, ptr) }
+}
+
+func (gen *decoderGenerator) value(t reflect.Type, tag reflect.StructTag, level int, stops string) (decodeCall, error) {
+	var helper string
+	switch t.Kind() {
+	case reflect.String:
+		return func(ptr string) string { return fmt.Sprintf("parse.DecodeString(input, %s, %q)", ptr, stops) }, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		helper = "DecodeInt"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		helper = "DecodeUint"
+	case reflect.Struct:
+		f, err := gen.structFunc(t, tag.Get("format"), stops)
+		return call(f), err
+	case reflect.Slice:
+		f, err := gen.slice(t, tag, level, stops)
+		return call(f), err
+	default:
+		return nil, fmt.Errorf("can't decode %v", t)
+	}
+	return func(ptr string) string { return fmt.Sprintf("parse.%s(input, %s, %q)", helper, ptr, t.String()) }, nil
+}
+
+func (gen *decoderGenerator) slice(t reflect.Type, tag reflect.StructTag, level int, stops string) (string, error) {
+	layout, err := sliceLayout(tag, level, stops)
+	if err != nil {
+		return "", err
+	}
+	elem, err := gen.value(t.Elem(), tag, level+1, layout.elemStops)
+	if err != nil {
+		return "", err
+	}
+	gen.slices = true
+
+	var body strings.Builder
+	// Growing nil gives an empty slice rather than nil, like Format does.
+	body.WriteString("s := slices.Grow(*v, 1)\n")
+	if layout.ambiguous {
+		body.WriteString("var beforeSep []byte\n")
+	}
+	if layout.opening != nil {
+		body.WriteString("var err error\n")
+		fmt.Fprintf(&body, "if input, err = parse.DecodeLiteral(input, %q); err != nil {\nreturn nil, err\n}\n", layout.opening)
+	}
+	body.WriteString("for i := 0; ; i++ {\n")
+	if layout.closing != nil {
+		fmt.Fprintf(&body, "if i == 0 && len(input) > 0 && input[0] == %q {\n// Empty list.\nbreak\n}\n", layout.closing[0])
+	}
+	fmt.Fprintf(&body, "s = slices.Grow(s, 1)[:i+1]\nrest, err := %s\nif err != nil {\n", elem("&s[i]"))
+	if layout.ambiguous {
+		body.WriteString("if i > 0 {\n// Put the separator back, it wasn't ours.\ninput, s = beforeSep, s[:i]\nbreak\n}\n")
+	}
+	body.WriteString("return nil, parse.NewFieldError(\"[\"+strconv.Itoa(i)+\"]\", err)\n}\n")
+	body.WriteString("input = rest\n")
+	sep := layout.sep
+	fmt.Fprintf(&body, "if len(input) < %d || string(input[:%d]) != %q {\nbreak\n}\n", len(sep), len(sep), sep)
+	if layout.ambiguous {
+		fmt.Fprintf(&body, "beforeSep, input = input, input[%d:]\n", len(sep))
+	} else {
+		fmt.Fprintf(&body, "input = input[%d:]\n", len(sep))
+	}
+	body.WriteString("}\n")
+	if layout.closing != nil {
+		fmt.Fprintf(&body, "if input, err = parse.DecodeLiteral(input, %q); err != nil {\nreturn nil, err\n}\n", layout.closing)
+	}
+	body.WriteString("*v = s\nreturn input, nil\n")
+	typ, err := gen.typeName(t)
+	if err != nil {
+		return "", err
+	}
+	comment := fmt.Sprintf("%s separated by %q", typ, sep)
+	if layout.opening != nil {
+		comment += fmt.Sprintf(" in %s%s", layout.opening, layout.closing)
+	}
+	return gen.function(t, comment, body.String())
+}
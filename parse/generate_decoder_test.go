@@ -0,0 +1,132 @@
+package parse_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+var update = flag.Bool("update", false, "rewrite the generated code instead of checking it")
+
+// This is in its own package because the generated code has to import parse.
+// Run it with -update to rewrite decoder_generated_test.go.
+
+type genLabel string
+
+type genPoint struct {
+	X, Y int8
+}
+
+type genRange struct {
+	Lo, Hi uint16
+}
+
+type genRecord struct {
+	Name   genLabel
+	Points []genPoint `sep:";" brackets:"[]"`
+	Ranges []genRange `format:"{lo}-{hi}"`
+	Grid   [][]uint8  `sep:" " brackets1:"()"`
+	Words  []string   `sep:" "`
+	Count  int
+}
+
+const genFormat = "{name}: {points} {ranges} {grid} #{count} | {words}"
+
+var genDecoder = parse.MustDecoder[genRecord](genFormat)
+
+func TestGenerateUpToDate(t *testing.T) {
+	var got bytes.Buffer
+	if err := genDecoder.Generate(&got, "parse_test", "decodeRecord"); err != nil {
+		t.Fatal(err)
+	}
+	const file = "decoder_generated_test.go"
+	if *update {
+		if err := os.WriteFile(file, got.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("%s is out of date, run the test with -update", file)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	type local struct{ A struct{ B int } }
+	for _, d := range []interface {
+		Generate(w *bytes.Buffer) error
+	}{
+		generator[local]{parse.MustDecoder[local]("")},
+		generator[genPoint]{parse.DecoderOf(decodeRecordPoint)},
+	} {
+		if err := d.Generate(&bytes.Buffer{}); err == nil {
+			t.Errorf("%T: no error", d)
+		}
+	}
+}
+
+type generator[T any] struct{ d *parse.Decoder[T] }
+
+func (g generator[T]) Generate(w *bytes.Buffer) error { return g.d.Generate(w, "p", "f") }
+
+// decodeRecordPoint is any parser at all.
+func decodeRecordPoint(s *parse.State, input []byte) (parse.ParseResult[genPoint], error) {
+	return parse.Format[genPoint]("")(s, input)
+}
+
+// TestGenerateSame checks that the generated code gets exactly the same
+// results and errors as the Decoder it came from, for a good line and lots of
+// broken versions of it.
+func TestGenerateSame(t *testing.T) {
+	const good = "abc: [1,2;-3,4] 1-2,30-40 (1,2) () (3) #-5 | x yz w"
+	var inputs []string
+	for _, extra := range []string{"", "[", "(", ")", "]", "-", " ", ",", ";", "|", "#", "9", "x", ":"} {
+		for i := range len(good) + 1 {
+			inputs = append(inputs,
+				good[:i]+extra+good[i:],
+				good[:i]+extra)
+			if i < len(good) {
+				inputs = append(inputs, good[:i]+extra+good[i+1:])
+			}
+		}
+	}
+	inputs = append(inputs,
+		"abc: [127,-128] 65535-0 () #9223372036854775807 | x",
+		"abc: [128,0] 1-2 () #1 | x",
+		"abc: [0,0] 65536-2 () #1 | x",
+		"abc: [0,0] 1-2 (256) #1 | x",
+		"abc: [0,0] 1-2 () #9223372036854775808 | x",
+		": [] 1-2 () #0 | ",
+	)
+
+	generated := parse.DecoderOf(decodeRecord)
+	for _, in := range inputs {
+		want, wantErr := genDecoder.Decode([]byte(in))
+		got, err := generated.Decode([]byte(in))
+		if errString(err) != errString(wantErr) {
+			t.Errorf("%q: got error %v, want %v", in, err, wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %+v, want %+v", in, got, want)
+		}
+	}
+	if _, err := genDecoder.Decode([]byte(good)); err != nil {
+		t.Errorf("%q: %v", good, err)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return strings.TrimSpace(err.Error())
+}
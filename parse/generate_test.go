@@ -0,0 +1,33 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	var src bytes.Buffer
+	if err := MustGrammar(devicesGrammar).Generate(&src, "devices", "parseDevices"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "devices.go", src.Bytes(), 0); err != nil {
+		t.Errorf("generated code doesn't parse: %v", err)
+	}
+
+	for _, g := range []string{
+		`a = a "x" | "y"`,
+		`a = ws b; b = "x"? a`,
+		`a = (b % "," | "x") "y"; b = /x*/ a`,
+	} {
+		err := MustGrammar(g).Generate(&src, "p", "f")
+		if !errors.Is(err, ErrLeftRecursion) {
+			t.Errorf("Generate(%q): got error %v, want ErrLeftRecursion", g, err)
+		}
+	}
+	if err := MustGrammar(`a = "x" a | "y"`).Generate(&src, "p", "f"); err != nil {
+		t.Errorf("right recursion: %v", err)
+	}
+}
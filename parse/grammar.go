@@ -0,0 +1,583 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Grammar is a grammar written in a small EBNF-like notation, which can be
+// turned into a parser at runtime. For example, day eleven's input is
+//
+//	devices = device % nl
+//	device  = name:word ":" outputs:(ws word)+
+//
+// Each rule is a name, an "=" and a clause, and rules are separated by
+// newlines or semicolons. The first rule is where parsing starts. Anything
+// after a # is a comment. Clauses are made up of:
+//
+//	"literal"  exactly that text (with Go escapes)
+//	/regexp/   a regular expression, with / written \/
+//	name       another rule, or one of the builtins: int, uint, hex, word
+//	           (letters), ws (spaces and tabs), nl (a newline), rest (the
+//	           rest of the line) or end (the end of the input)
+//	a b        a then b
+//	a | b      a or b
+//	a* a+ a?   zero or more, one or more, or zero or one a
+//	a % b      one or more a separated by b
+//	name:a     a, captured as name
+//	(a)        grouping
+type Grammar struct {
+	Rules []*Rule
+}
+
+// Rule is a single named rule in a Grammar.
+type Rule struct {
+	Name   string
+	Clause *Clause
+}
+
+// ClauseKind is the kind of a Clause.
+type ClauseKind uint8
+
+const (
+	ClauseLiteral  ClauseKind = iota // Text is the literal
+	ClauseRegexp                     // Text is the regular expression
+	ClauseBuiltin                    // Text is the builtin's name
+	ClauseRule                       // Text is the rule's name
+	ClauseSeq                        // each of Args in turn
+	ClauseChoice                     // the first of Args that matches
+	ClauseMany                       // Args[0] zero or more times
+	ClauseSome                       // Args[0] one or more times
+	ClauseOptional                   // Args[0] zero or one times
+	ClauseSepBy                      // Args[0] one or more times, separated by Args[1]
+	ClauseCapture                    // Args[0], captured as Text
+)
+
+// Clause is a piece of a Rule.
+type Clause struct {
+	Kind ClauseKind
+	Text string
+	Args []*Clause
+}
+
+// String returns the clause in the grammar notation.
+func (c *Clause) String() string {
+	return c.format(0)
+}
+
+// format formats c to go somewhere that binds at least as tightly as level:
+// 0 for a choice, then sequences, captures, separated lists, repetition and
+// finally atoms.
+func (c *Clause) format(level int) string {
+	var (
+		s    string
+		mine int
+	)
+	switch c.Kind {
+	case ClauseLiteral:
+		return strconv.Quote(c.Text)
+	case ClauseRegexp:
+		return "/" + strings.ReplaceAll(c.Text, "/", `\/`) + "/"
+	case ClauseBuiltin, ClauseRule:
+		return c.Text
+	case ClauseChoice, ClauseSeq:
+		sep, argLevel := " | ", 1
+		if c.Kind == ClauseSeq {
+			sep, argLevel, mine = " ", 2, 1
+		}
+		parts := make([]string, len(c.Args))
+		for i, a := range c.Args {
+			parts[i] = a.format(argLevel)
+		}
+		s = strings.Join(parts, sep)
+	case ClauseCapture:
+		s, mine = c.Text+":"+c.Args[0].format(3), 2
+	case ClauseSepBy:
+		s, mine = c.Args[0].format(4)+" % "+c.Args[1].format(4), 3
+	case ClauseMany, ClauseSome, ClauseOptional:
+		op := map[ClauseKind]string{ClauseMany: "*", ClauseSome: "+", ClauseOptional: "?"}[c.Kind]
+		s, mine = c.Args[0].format(4)+op, 4
+	}
+	if mine < level {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// builtins are the parsers that grammars get for free. Those that return
+// true are kept in the tree, the others are just skipped over.
+var builtins = map[string]struct {
+	p    Parser[[]byte]
+	keep bool
+}{
+	"int":  {Span(Int[int64]), true},
+	"uint": {Span(Uint[uint64]), true},
+	"hex":  {Span(Hex[uint64]), true},
+	"word": {Span(Regexp[string](`[A-Za-z]+`)), true},
+	"rest": {Span(Regexp[string](`[^\n]*`)), true},
+	"ws":   {Span(Spaces), false},
+	"nl":   {Span(Byte('\n')), false},
+	"end":  {Span(End), false},
+}
+
+// grammarNotation returns a parser for the notation itself, which records
+// its failures in f.
+func grammarNotation(f *furthest) Parser[[]*Rule] {
+	var (
+		token   = func(s string) Parser[string] { return lexeme(expect(f, strconv.Quote(s), Literal(s))) }
+		ident   = lexeme(expect(f, "a name", Regexp[string](`[A-Za-z_][A-Za-z0-9_]*`)))
+		quoted  = lexeme(expect(f, "a string", ApplyErr(Regexp[string](`"(?:[^"\\\n]|\\.)*"`), strconv.Unquote)))
+		pattern = lexeme(expect(f, "a regexp", ApplyErr(Regexp[string](`/(?:[^/\\\n]|\\.)*/`), func(s string) (string, error) {
+			s = strings.ReplaceAll(s[1:len(s)-1], `\/`, `/`)
+			_, err := regexp.Compile(s)
+			return s, err
+		})))
+		choice = NewRef[*Clause]()
+		atom   = Or(
+			Apply(quoted, func(s string) *Clause { return &Clause{Kind: ClauseLiteral, Text: s} }),
+			Apply(pattern, func(s string) *Clause { return &Clause{Kind: ClauseRegexp, Text: s} }),
+			Between(token("("), choice.Parser(), token(")")),
+			Apply(ident, func(s string) *Clause { return &Clause{Kind: ClauseRule, Text: s} }),
+		)
+		postfix = Apply(Seq(atom, Many(Or(token("*"), token("+"), token("?")))), func(p Pair[*Clause, []string]) *Clause {
+			c := p.First
+			for _, op := range p.Second {
+				kind := map[string]ClauseKind{"*": ClauseMany, "+": ClauseSome, "?": ClauseOptional}[op]
+				c = &Clause{Kind: kind, Args: []*Clause{c}}
+			}
+			return c
+		})
+		sepBy = Apply(Seq(postfix, Or(SeqR(token("%"), postfix), Pure[*Clause](nil))), func(p Pair[*Clause, *Clause]) *Clause {
+			if p.Second == nil {
+				return p.First
+			}
+			return &Clause{Kind: ClauseSepBy, Args: []*Clause{p.First, p.Second}}
+		})
+		item = Or(
+			Apply(Seq(SeqL(ident, token(":")), sepBy), func(p Pair[string, *Clause]) *Clause {
+				return &Clause{Kind: ClauseCapture, Text: p.First, Args: []*Clause{p.Second}}
+			}),
+			sepBy,
+		)
+		seq = Apply(Some(item), func(items []*Clause) *Clause {
+			if len(items) == 1 {
+				return items[0]
+			}
+			return &Clause{Kind: ClauseSeq, Args: items}
+		})
+		rule = Apply(Seq(SeqL(ident, token("=")), choice.Parser()), func(p Pair[string, *Clause]) *Rule {
+			return &Rule{Name: p.First, Clause: p.Second}
+		})
+		separator = Some(lexeme(expect(f, "end of rule", Or(Span(Byte('\n')), Span(Byte(';')), Span(Regexp[string](`#[^\n]*`))))))
+	)
+	choice.Set(Apply(SepBy(seq, token("|")), func(alts []*Clause) *Clause {
+		if len(alts) == 1 {
+			return alts[0]
+		}
+		return &Clause{Kind: ClauseChoice, Args: alts}
+	}))
+	return SeqR(Many(separator), SeqL(SepBy(rule, separator), SeqL(Many(separator), lexeme(expect(f, "end of grammar", End)))))
+}
+
+// ParseGrammar parses a grammar written in the notation described on
+// Grammar.
+func ParseGrammar(src string) (*Grammar, error) {
+	var (
+		input = []byte(src)
+		f     furthest
+	)
+	r, err := grammarNotation(&f)(nil, input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grammar: %w", f.error(input, err))
+	}
+	g := &Grammar{Rules: r.result}
+	if err := g.resolve(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// MustGrammar is like ParseGrammar, but panics if the grammar is invalid.
+func MustGrammar(src string) *Grammar {
+	g, err := ParseGrammar(src)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// resolve works out which names refer to rules and which to builtins.
+func (g *Grammar) resolve() error {
+	rules := make(map[string]bool)
+	for _, r := range g.Rules {
+		if _, ok := builtins[r.Name]; ok {
+			return fmt.Errorf("rule %q has the same name as a builtin", r.Name)
+		}
+		if rules[r.Name] {
+			return fmt.Errorf("rule %q defined twice", r.Name)
+		}
+		rules[r.Name] = true
+	}
+	var walk func(c *Clause) error
+	walk = func(c *Clause) error {
+		if c.Kind == ClauseRule && !rules[c.Text] {
+			if _, ok := builtins[c.Text]; !ok {
+				return fmt.Errorf("undefined rule %q", c.Text)
+			}
+			c.Kind = ClauseBuiltin
+		}
+		for _, a := range c.Args {
+			if err := walk(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, r := range g.Rules {
+		if err := walk(r.Clause); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// Node is a node in the tree produced by a Grammar. Literals and the builtins
+// that only skip over things don't make nodes, and neither does a sequence
+// with only one thing in it worth keeping.
+type Node struct {
+	Name     string // of the capture or rule that made it, if any
+	Text     string // the input it was parsed from
+	Children []*Node
+}
+
+// Parser builds a parser for the grammar, starting at its first rule.
+func (g *Grammar) Parser() Parser[*Node] {
+	return g.build(nil)
+}
+
+// Parse parses a whole input, apart from any trailing whitespace. Errors are
+// reported wherever parsing got furthest before failing, with everything
+// that could have come next there.
+func (g *Grammar) Parse(input []byte) (*Node, error) {
+	var f furthest
+	r, err := SeqL(g.build(&f), expect(&f, "end of input", Regexp[string](`\s*$`)))(nil, input)
+	if err != nil {
+		return nil, f.error(input, err)
+	}
+	return r.result, nil
+}
+
+// build builds a parser for the grammar, which records its failures in f if
+// it isn't nil.
+func (g *Grammar) build(f *furthest) Parser[*Node] {
+	b := &grammarBuilder{
+		refs:     make(map[string]*Ref[*Node], len(g.Rules)),
+		furthest: f,
+	}
+	for _, r := range g.Rules {
+		b.refs[r.Name] = NewRef[*Node]()
+	}
+	for _, r := range g.Rules {
+		b.refs[r.Name].Set(Label(r.Name, b.clause(r.Clause)))
+	}
+	return b.clause(&Clause{Kind: ClauseRule, Text: g.Rules[0].Name})
+}
+
+type grammarBuilder struct {
+	refs     map[string]*Ref[*Node]
+	furthest *furthest
+}
+
+func (b *grammarBuilder) clause(c *Clause) Parser[*Node] {
+	args := make([]Parser[*Node], len(c.Args))
+	for i, a := range c.Args {
+		args[i] = b.clause(a)
+	}
+	leaf := func(s []byte) *Node { return &Node{Text: string(s)} }
+	skip := func([]byte) *Node { return nil }
+
+	switch c.Kind {
+	case ClauseLiteral:
+		return Apply(expect(b.furthest, strconv.Quote(c.Text), Span(Literal(c.Text))), skip)
+	case ClauseRegexp:
+		return Apply(expect(b.furthest, "/"+c.Text+"/", Span(Regexp[string](c.Text))), leaf)
+	case ClauseBuiltin:
+		builtin := builtins[c.Text]
+		p := expect(b.furthest, c.Text, builtin.p)
+		if builtin.keep {
+			return Apply(p, leaf)
+		}
+		return Apply(p, skip)
+	case ClauseRule:
+		return Apply(b.refs[c.Text].Parser(), func(n *Node) *Node {
+			if n != nil && n.Name == "" {
+				n.Name = c.Text
+			}
+			return n
+		})
+	case ClauseSeq:
+		return withText(func(s *State, input []byte) (ParseResult[*Node], error) {
+			var kept []*Node
+			for _, p := range args {
+				r, err := p(s, input)
+				if err != nil {
+					return ParseResult[*Node]{}, err
+				}
+				if r.result != nil {
+					kept = append(kept, r.result)
+				}
+				input = r.remainder
+			}
+			var n *Node
+			switch len(kept) {
+			case 0:
+			case 1:
+				n = kept[0]
+			default:
+				n = &Node{Children: kept}
+			}
+			return ParseResult[*Node]{result: n, remainder: input}, nil
+		})
+	case ClauseChoice:
+		return Or(args...)
+	case ClauseMany:
+		return withText(Apply(Many(args[0]), list))
+	case ClauseSome:
+		return withText(Apply(Some(args[0]), list))
+	case ClauseOptional:
+		return Or(args[0], Pure[*Node](nil))
+	case ClauseSepBy:
+		return withText(Apply(SepBy(args[0], args[1]), list))
+	case ClauseCapture:
+		return func(s *State, input []byte) (ParseResult[*Node], error) {
+			r, err := args[0](s, input)
+			if err != nil {
+				return ParseResult[*Node]{}, err
+			}
+			n := r.result
+			if n == nil {
+				n = &Node{Text: string(input[:len(input)-len(r.remainder)])}
+			}
+			n.Name = c.Text
+			r.result = n
+			return r, nil
+		}
+	}
+	panic(fmt.Sprintf("unknown clause kind %d", c.Kind))
+}
+
+// furthest remembers the failures that got furthest into the input. After
+// backtracking, the error that finally comes out is usually about something
+// much earlier, like a list ending sooner than it should have, so these make
+// for a much better error.
+type furthest struct {
+	remaining int // of the input, after the failures
+	wants     []string
+	err       error // the first failure that wasn't just a missing thing
+}
+
+// expect returns a parser that behaves like p, but if it fails without
+// getting anywhere it says it wanted want, and if f isn't nil the failure is
+// recorded in it.
+func expect[A any](f *furthest, want string, p Parser[A]) Parser[A] {
+	return func(s *State, input []byte) (ParseResult[A], error) {
+		r, err := p(s, input)
+		if err == nil || fatal(err) {
+			return r, err
+		}
+		if u, ok := err.(*unexpected); ok && u.remaining == len(input) {
+			err = &unexpected{want: want, found: u.found, remaining: u.remaining}
+		}
+		if f != nil {
+			f.record(err, len(input))
+		}
+		return r, err
+	}
+}
+
+// record records a failure, remaining is the length of the input at the
+// parser that failed.
+func (f *furthest) record(err error, remaining int) {
+	u, ok := err.(*unexpected)
+	if ok {
+		remaining = u.remaining
+	}
+	switch {
+	case len(f.wants) == 0 && f.err == nil, remaining < f.remaining:
+		*f = furthest{remaining: remaining}
+	case remaining > f.remaining:
+		return
+	}
+	switch {
+	case !ok:
+		if f.err == nil {
+			f.err = err
+		}
+	case !slices.Contains(f.wants, u.want):
+		f.wants = append(f.wants, u.want)
+	}
+}
+
+// error returns whichever of the recorded failures and err got furthest into
+// input, as a ParseError.
+func (f *furthest) error(input []byte, err error) *ParseError {
+	remaining := len(input)
+	var u *unexpected
+	if errors.As(err, &u) {
+		remaining = u.remaining
+	}
+	switch {
+	case len(f.wants) == 0 && f.err == nil, remaining <= f.remaining:
+		return newParseError(input, 0, err)
+	case f.err != nil:
+		return newParseError(input, len(input)-f.remaining, f.err)
+	}
+	return newParseError(input, 0, &unexpected{
+		want:      strings.Join(f.wants, " or "),
+		found:     string(truncate(input[len(input)-f.remaining:])),
+		remaining: f.remaining,
+	})
+}
+
+// list makes a node out of a list of things, leaving out any gaps.
+func list(ns []*Node) *Node {
+	return &Node{Children: slices.DeleteFunc(ns, func(n *Node) bool { return n == nil })}
+}
+
+// withText fills in the Text of the node p makes, if it doesn't have one.
+func withText(p Parser[*Node]) Parser[*Node] {
+	return func(s *State, input []byte) (ParseResult[*Node], error) {
+		r, err := p(s, input)
+		if err == nil && r.result != nil && r.result.Text == "" {
+			r.result.Text = string(input[:len(input)-len(r.remainder)])
+		}
+		return r, err
+	}
+}
+
+// String returns the tree as indented text, one node per line.
+func (n *Node) String() string {
+	var (
+		sb    strings.Builder
+		write func(n *Node, depth int)
+	)
+	write = func(n *Node, depth int) {
+		sb.WriteString(strings.Repeat("  ", depth))
+		name := n.Name
+		if name == "" {
+			name = "-"
+		}
+		sb.WriteString(name)
+		if len(n.Children) == 0 {
+			fmt.Fprintf(&sb, " %q", n.Text)
+		}
+		sb.WriteByte('\n')
+		for _, c := range n.Children {
+			write(c, depth+1)
+		}
+	}
+	write(n, 0)
+	return sb.String()
+}
+
+// Find returns the first node below n with the given name (ignoring case),
+// without looking inside any other named nodes.
+func (n *Node) Find(name string) *Node {
+	for _, c := range n.Children {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+		if c.Name == "" {
+			if found := c.Find(name); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// Decode fills in the value v points to from the tree. Exported struct
+// fields are filled from the nodes with the same name, matched the same way
+// as a Format; slices from a node's children; and strings and numbers from a
+// node's text.
+func (n *Node) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("Decode needs a non-nil pointer")
+	}
+	return decodeNode(n, rv.Elem())
+}
+
+func decodeNode(n *Node, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(n.Text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(n.Text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(n.Text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Slice:
+		elems := n.Children
+		if len(elems) == 0 && n.Text != "" {
+			elems = []*Node{n}
+		}
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, e := range elems {
+			if err := decodeNode(e, s.Index(i)); err != nil {
+				return NewFieldError("["+strconv.Itoa(i)+"]", err)
+			}
+		}
+		v.Set(s)
+	case reflect.Struct:
+		for i, name := range fieldNames(v.Type()) {
+			if name == "-" {
+				continue
+			}
+			c := n.Find(name)
+			if c == nil && strings.EqualFold(n.Name, name) {
+				// A list of captures can fill a slice of
+				// structs with one field each.
+				c = n
+			}
+			if c == nil {
+				continue
+			}
+			if err := decodeNode(c, v.Field(i)); err != nil {
+				return NewFieldError(name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("can't decode into %v", v.Type())
+	}
+	return nil
+}
+
+// structFields caches fieldNames, since Decode looks them up for every node.
+var structFields sync.Map // of reflect.Type to []string
+
+// fieldNames returns the names the fields of a struct type are captured with.
+func fieldNames(t reflect.Type) []string {
+	if names, ok := structFields.Load(t); ok {
+		return names.([]string)
+	}
+	names := make([]string, t.NumField())
+	for i := range names {
+		names[i] = fieldName(t.Field(i))
+	}
+	structFields.Store(t, names)
+	return names
+}
@@ -0,0 +1,122 @@
+package parse
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const devicesGrammar = `
+# Day eleven.
+devices = device % nl
+device  = name:word ":" outputs:(ws word)+
+`
+
+func TestGrammar(t *testing.T) {
+	g, err := ParseGrammar(devicesGrammar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := g.Parse([]byte("aaa: you hhh\nyou: bbb\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `devices
+  device
+    name "aaa"
+    outputs
+      - "you"
+      - "hhh"
+  device
+    name "you"
+    outputs
+      - "bbb"
+`
+	if got := n.String(); got != want {
+		t.Errorf("got tree:\n%s\nwant:\n%s", got, want)
+	}
+
+	type device struct {
+		Name    string
+		Outputs []string
+	}
+	var devices []device
+	if err := n.Decode(&devices); err != nil {
+		t.Fatal(err)
+	}
+	wantDevices := []device{
+		{Name: "aaa", Outputs: []string{"you", "hhh"}},
+		{Name: "you", Outputs: []string{"bbb"}},
+	}
+	if !reflect.DeepEqual(devices, wantDevices) {
+		t.Errorf("got %+v, want %+v", devices, wantDevices)
+	}
+
+	_, err = g.Parse([]byte("aaa: you\nbbb ccc\n"))
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 || pe.Column != 4 {
+		t.Errorf("got error %v, want one at 2:4", err)
+	}
+}
+
+func TestGrammarChoice(t *testing.T) {
+	g := MustGrammar(`turns = turn % ","; turn = dir:("L" | "R") steps:uint sign:"!"?`)
+	n, err := g.Parse([]byte("L10,R2!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type turn struct {
+		Dir   string
+		Steps int
+		Sign  string
+	}
+	var got []turn
+	if err := n.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := []turn{{"L", 10, ""}, {"R", 2, "!"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	n, err = MustGrammar(`xs = (x:int) % ","`).Parse([]byte("1,1000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var small []struct{ X int8 }
+	if err := n.Decode(&small); err == nil {
+		t.Error("decoding 1000 into an int8: no error")
+	}
+}
+
+func TestGrammarErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src, want string
+	}{
+		{`a = b`, `undefined rule "b"`},
+		{`a = "x"; a = "y"`, `defined twice`},
+		{`int = "x"`, `same name as a builtin`},
+		{`a = "x" |`, `column 10: expected a name or a string`},
+		{`a = /[/`, `missing closing ]`},
+		{`a = ("x"`, `or ")"`},
+	} {
+		_, err := ParseGrammar(tc.src)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("ParseGrammar(%q): got error %v, want one containing %q", tc.src, err, tc.want)
+		}
+	}
+}
+
+func TestClauseString(t *testing.T) {
+	src := `a = x:(b | "c\n")+ (b % ",")? /[\/]+/; b = (int ws)* % (nl | ";")`
+	g := MustGrammar(src)
+	var rules []string
+	for _, r := range g.Rules {
+		rules = append(rules, r.Name+" = "+r.Clause.String())
+	}
+	again := MustGrammar(strings.Join(rules, "\n"))
+	if !reflect.DeepEqual(g, again) {
+		t.Errorf("%s doesn't parse back to the same grammar", strings.Join(rules, "; "))
+	}
+}
@@ -0,0 +1,106 @@
+package parse
+
+import "sync"
+
+// Memo is a packrat cache for parsers. Wrapping the parsers in a grammar that
+// can be tried many times at the same point in the input with Memoise means
+// that when the grammar is run by RunMemo they are only ever run once per
+// position, so a grammar full of choices can't take exponential time.
+// Anywhere else, including Run, they behave as usual, so it costs nothing if
+// it isn't used.
+//
+// The cache itself belongs to each call to RunMemo and is thrown away at the
+// end of it, so the same Memo can be used by concurrent runs, but then Stats
+// only says how the last one to finish went.
+type Memo struct {
+	mu     sync.Mutex
+	nextID int
+	stats  MemoStats
+}
+
+// MemoStats are statistics about how useful a Memo has been.
+type MemoStats struct {
+	Hits, Misses int
+	Entries      int // in the cache at the end of the run
+}
+
+// memoRun is the cache for a single run of RunMemo.
+type memoRun struct {
+	memo         *Memo
+	entries      map[memoKey]any
+	hits, misses int
+}
+
+// memoKey is a memoised parser and the offset in the whole input it was run
+// at.
+type memoKey struct {
+	id, offset int
+}
+
+type memoEntry[In Input, A any] struct {
+	result ResultOf[In, A]
+	err    error
+}
+
+// NewMemo returns an empty Memo.
+func NewMemo() *Memo {
+	return &Memo{}
+}
+
+// Memoise returns a parser that runs p, caching its results in the run of
+// RunMemo with m that it's part of so it's only run once for each position in
+// the input.
+func Memoise[In Input, A any](m *Memo, p ParserOf[In, A]) ParserOf[In, A] {
+	m.mu.Lock()
+	id := m.nextID
+	m.nextID++
+	m.mu.Unlock()
+
+	return func(s *State, input In) (ResultOf[In, A], error) {
+		if s == nil || s.memo == nil || s.memo.memo != m {
+			return p(s, input)
+		}
+		run := s.memo
+		key := memoKey{id: id, offset: s.offset(len(input))}
+		if e, ok := run.entries[key]; ok {
+			run.hits++
+			e := e.(memoEntry[In, A])
+			return e.result, e.err
+		}
+		run.misses++
+		result, err := p(s, input)
+		run.entries[key] = memoEntry[In, A]{result: result, err: err}
+		return result, err
+	}
+}
+
+// RunMemo is like Run, but caches the results of parsers that were wrapped
+// with Memoise(m, ...) for the length of the run.
+func RunMemo[In Input, A any](m *Memo, p ParserOf[In, A], input In) (A, error) {
+	s := newState(input)
+	s.memo = &memoRun{memo: m, entries: make(map[memoKey]any)}
+	a, err := runWith(s, p, input)
+	m.mu.Lock()
+	m.stats = MemoStats{
+		Hits:    s.memo.hits,
+		Misses:  s.memo.misses,
+		Entries: len(s.memo.entries),
+	}
+	m.mu.Unlock()
+	return a, err
+}
+
+// Reset resets the statistics.
+func (m *Memo) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats = MemoStats{}
+}
+
+// Stats returns the statistics for the last run with m to finish, or nothing
+// if it's been Reset since.
+func (m *Memo) Stats() MemoStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
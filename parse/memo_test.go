@@ -0,0 +1,153 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ambiguous returns a grammar for sums and differences of nested
+// parenthesised numbers that backtracks a lot:
+//
+//	expr = term '+' expr | term '-' expr | term
+//	term = '(' expr ')' | number
+//
+// Without memoisation each term is parsed three times per level of nesting.
+// If memo is nil, nothing is memoised.
+func ambiguous(memo *Memo) Parser[uint64] {
+	wrap := func(p Parser[uint64]) Parser[uint64] {
+		if memo == nil {
+			return p
+		}
+		return Memoise(memo, p)
+	}
+	var (
+		expr = NewRef[uint64]()
+		term = wrap(Or(Between(Byte('('), expr.Parser(), Byte(')')), Uint[uint64]))
+	)
+	expr.Set(wrap(Or(
+		Apply(Seq(SeqL(term, Byte('+')), expr.Parser()), func(p Pair[uint64, uint64]) uint64 {
+			return p.First + p.Second
+		}),
+		Apply(Seq(SeqL(term, Byte('-')), expr.Parser()), func(p Pair[uint64, uint64]) uint64 {
+			return p.First - p.Second
+		}),
+		term,
+	)))
+	return expr.Parser()
+}
+
+// nested returns an input with depth levels of nesting.
+func nested(depth int) []byte {
+	return []byte(strings.Repeat("(1+", depth) + "1" + strings.Repeat(")", depth))
+}
+
+func TestMemo(t *testing.T) {
+	var (
+		m     = NewMemo()
+		input = nested(8)
+	)
+	want, err := Run(ambiguous(nil), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := RunMemo(m, ambiguous(m), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("memoised: got %d, want %d", got, want)
+	}
+	if s := m.Stats(); s.Hits == 0 {
+		t.Errorf("got stats %+v, want some hits", s)
+	}
+}
+
+func TestMemoString(t *testing.T) {
+	m := NewMemo()
+	number := Memoise(m, UintOf[uint64, string])
+	// Both alternatives start with the same number.
+	p := Or(
+		SeqL(number, ByteOf[string]('!')),
+		number,
+	)
+	got, err := RunMemo(m, p, "12")
+	if err != nil || got != 12 {
+		t.Errorf("got %d, %v; want 12", got, err)
+	}
+	if s := m.Stats(); s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("got stats %+v, want one hit and one miss", s)
+	}
+}
+
+func TestMemoRuns(t *testing.T) {
+	m := NewMemo()
+	p := Memoise(m, Or(Uint[uint64], Apply(End, func(struct{}) uint64 { return 0 })))
+
+	// Nothing is left over from one run to the next, or used by Run, even
+	// when it's the same buffer.
+	input := []byte("123")
+	if got, err := RunMemo(m, p, input); err != nil || got != 123 {
+		t.Errorf("RunMemo(123) = %d, %v, want 123", got, err)
+	}
+	copy(input, "456")
+	if got, err := Run(p, input); err != nil || got != 456 {
+		t.Errorf("Run(456) = %d, %v, want 456", got, err)
+	}
+	if got, err := RunMemo(m, p, input); err != nil || got != 456 {
+		t.Errorf("RunMemo(456) = %d, %v, want 456", got, err)
+	}
+	if got, err := RunMemo(m, p, nil); err != nil || got != 0 {
+		t.Errorf("RunMemo(nil) = %d, %v, want 0", got, err)
+	}
+	if s := m.Stats(); s != (MemoStats{Misses: 1, Entries: 1}) {
+		t.Errorf("got stats %+v after one parse, want one miss", s)
+	}
+}
+
+func TestMemoConcurrent(t *testing.T) {
+	var (
+		m = NewMemo()
+		p = ambiguous(m)
+	)
+	var wg sync.WaitGroup
+	for i := range 16 {
+		input := nested(i % 8)
+		wg.Go(func() {
+			for range 20 {
+				if got, err := RunMemo(m, p, input); err != nil || got != uint64(i%8+1) {
+					t.Errorf("RunMemo(%s) = %d, %v, want %d", input, got, err, i%8+1)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func BenchmarkAmbiguous(b *testing.B) {
+	for _, depth := range []int{4, 8, 16, 32, 64} {
+		input := nested(depth)
+		if depth <= 8 {
+			// Much past this and we'll be here all day.
+			b.Run(fmt.Sprintf("plain/depth=%d", depth), func(b *testing.B) {
+				p := ambiguous(nil)
+				for b.Loop() {
+					if _, err := Run(p, input); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+		b.Run(fmt.Sprintf("memo/depth=%d", depth), func(b *testing.B) {
+			m := NewMemo()
+			p := ambiguous(m)
+			for b.Loop() {
+				if _, err := RunMemo(m, p, input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
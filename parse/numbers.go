@@ -0,0 +1,144 @@
+package parse
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Uint is a parser that parses a single unsigned decimal integer made up of
+// ASCII digits. It fails with ErrOverflow if the number doesn't fit in a U.
+func Uint[U constraints.Unsigned](s *State, input []byte) (ParseResult[U], error) {
+	return UintOf[U](s, input)
+}
+
+// UintOf is Uint for any type of input.
+func UintOf[U constraints.Unsigned, In Input](_ *State, input In) (ResultOf[In, U], error) {
+	var (
+		limit = ^U(0)
+		n     U
+		end   = 0
+	)
+	for ; end < len(input); end++ {
+		d := U(input[end] - '0')
+		if d > 9 {
+			break
+		}
+		if n > (limit-d)/10 {
+			return ResultOf[In, U]{}, overflowError(input)
+		}
+		n = n*10 + d
+	}
+	if end == 0 {
+		_, err := unexpectedError("a number", input)
+		return ResultOf[In, U]{}, err
+	}
+	return ResultOf[In, U]{
+		result:    n,
+		remainder: input[end:],
+	}, nil
+}
+
+// Int is a parser that parses a single signed decimal integer made up of
+// ASCII digits, with an optional leading sign. It fails with ErrOverflow if
+// the number doesn't fit in an S.
+func Int[S constraints.Signed](s *State, input []byte) (ParseResult[S], error) {
+	return IntOf[S](s, input)
+}
+
+// IntOf is Int for any type of input.
+func IntOf[S constraints.Signed, In Input](_ *State, input In) (ResultOf[In, S], error) {
+	var (
+		s     S
+		limit = uint64(1)<<(8*unsafe.Sizeof(s)-1) - 1
+		neg   = false
+		start = 0
+	)
+	if len(input) > 0 && (input[0] == '-' || input[0] == '+') {
+		neg = input[0] == '-'
+		start = 1
+	}
+	if neg {
+		// One more on the negative side.
+		limit++
+	}
+	var (
+		n   uint64
+		end = start
+	)
+	for ; end < len(input); end++ {
+		d := uint64(input[end] - '0')
+		if d > 9 {
+			break
+		}
+		if n > (limit-d)/10 {
+			return ResultOf[In, S]{}, overflowError(input)
+		}
+		n = n*10 + d
+	}
+	if end == start && start == len(input) && start > 0 {
+		// Just a sign so far.
+		_, err := endedEarly("a number", input)
+		return ResultOf[In, S]{}, err
+	}
+	if end == start {
+		_, err := unexpectedError("a number", input)
+		return ResultOf[In, S]{}, err
+	}
+	result := S(n)
+	if neg {
+		// Going via int64 so that the most negative number works.
+		result = S(-int64(n))
+	}
+	return ResultOf[In, S]{
+		result:    result,
+		remainder: input[end:],
+	}, nil
+}
+
+// Hex is a parser that parses a single unsigned hexadecimal integer, without
+// any prefix, made up of ASCII digits and upper or lower case letters. It
+// fails with ErrOverflow if the number doesn't fit in a U.
+func Hex[U constraints.Unsigned](s *State, input []byte) (ParseResult[U], error) {
+	return HexOf[U](s, input)
+}
+
+// HexOf is Hex for any type of input.
+func HexOf[U constraints.Unsigned, In Input](_ *State, input In) (ResultOf[In, U], error) {
+	var (
+		limit = ^U(0) >> 4
+		n     U
+		end   = 0
+	)
+loop:
+	for ; end < len(input); end++ {
+		var d U
+		switch c := input[end]; {
+		case c >= '0' && c <= '9':
+			d = U(c - '0')
+		case c >= 'a' && c <= 'f':
+			d = U(c - 'a' + 10)
+		case c >= 'A' && c <= 'F':
+			d = U(c - 'A' + 10)
+		default:
+			break loop
+		}
+		if n > limit {
+			return ResultOf[In, U]{}, overflowError(input)
+		}
+		n = n<<4 | d
+	}
+	if end == 0 {
+		_, err := unexpectedError("a hexadecimal number", input)
+		return ResultOf[In, U]{}, err
+	}
+	return ResultOf[In, U]{
+		result:    n,
+		remainder: input[end:],
+	}, nil
+}
+
+func overflowError[In Input](input In) error {
+	return fmt.Errorf("%w: %q", ErrOverflow, []byte(truncate(input)))
+}
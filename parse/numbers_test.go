@@ -0,0 +1,161 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNumbers(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		p     func([]byte) (int64, string, error)
+		input string
+		want  int64
+		rest  string
+	}{
+		{name: "uint8", p: run(Uint[uint8]), input: "255,", want: 255, rest: ","},
+		{name: "uint16", p: run(Uint[uint16]), input: "0042x", want: 42, rest: "x"},
+		{name: "int8", p: run(Int[int8]), input: "-128", want: -128},
+		{name: "int8", p: run(Int[int8]), input: "+127-", want: 127, rest: "-"},
+		{name: "int64", p: run(Int[int64]), input: "-9223372036854775808", want: math.MinInt64},
+		{name: "hex", p: run(Hex[uint16]), input: "fF0a ", want: 0xff0a, rest: " "},
+	} {
+		got, rest, err := c.p([]byte(c.input))
+		if err != nil {
+			t.Errorf("%s(%q): %v", c.name, c.input, err)
+			continue
+		}
+		if got != c.want || rest != c.rest {
+			t.Errorf("%s(%q) = %d, %q; want %d, %q", c.name, c.input, got, rest, c.want, c.rest)
+		}
+	}
+}
+
+func TestNumbersErrors(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		p        func([]byte) (int64, string, error)
+		input    string
+		overflow bool
+	}{
+		{name: "uint8", p: run(Uint[uint8]), input: "256", overflow: true},
+		{name: "uint64", p: run(Uint[uint64]), input: "18446744073709551616", overflow: true},
+		{name: "int8", p: run(Int[int8]), input: "-129", overflow: true},
+		{name: "int8", p: run(Int[int8]), input: "128", overflow: true},
+		{name: "hex", p: run(Hex[uint8]), input: "100", overflow: true},
+		{name: "uint", p: run(Uint[uint]), input: "١٢"}, // not ASCII digits
+		{name: "int", p: run(Int[int]), input: "-"},
+		{name: "hex", p: run(Hex[uint]), input: "g"},
+	} {
+		_, _, err := c.p([]byte(c.input))
+		if err == nil {
+			t.Errorf("%s(%q): no error", c.name, c.input)
+			continue
+		}
+		if got := errors.Is(err, ErrOverflow); got != c.overflow {
+			t.Errorf("%s(%q): got error %v, want overflow: %t", c.name, c.input, err, c.overflow)
+		}
+	}
+}
+
+// run adapts a numeric parser for table tests.
+func run[N int8 | int | int64 | uint | uint8 | uint16 | uint64](p Parser[N]) func([]byte) (int64, string, error) {
+	return func(input []byte) (int64, string, error) {
+		r, err := p(nil, input)
+		return int64(r.result), string(r.remainder), err
+	}
+}
+
+func TestNumbersAllocs(t *testing.T) {
+	input := []byte("-1234567890123")
+	if n := testing.AllocsPerRun(100, func() { Int[int64](nil, input) }); n != 0 {
+		t.Errorf("Int allocated %v times, want 0", n)
+	}
+}
+
+// numberLines is the sort of thing the day readers see: lots of lines with a
+// number on each.
+var numberLines = func() [][]byte {
+	var lines [][]byte
+	for i := range 1000 {
+		lines = append(lines, strconv.AppendUint(nil, uint64(i)*2654435761, 10))
+	}
+	return lines
+}()
+
+func BenchmarkUint(b *testing.B) {
+	b.Run("strconv", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, l := range numberLines {
+				if _, err := strconv.ParseUint(string(l), 10, 64); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, l := range numberLines {
+				if _, err := Uint[uint64](nil, l); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkInt(b *testing.B) {
+	lines := make([][]byte, len(numberLines))
+	for i, l := range numberLines {
+		lines[i] = append([]byte{'-'}, l...)
+	}
+	b.Run("strconv", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, l := range lines {
+				if _, err := strconv.ParseInt(string(l), 10, 64); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, l := range lines {
+				if _, err := Int[int64](nil, l); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkHex(b *testing.B) {
+	lines := bytes.Fields([]byte("deadbeef cafef00d 0 ffffffffffffffff 1234abcd"))
+	b.Run("strconv", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, l := range lines {
+				if _, err := strconv.ParseUint(string(l), 16, 64); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, l := range lines {
+				if _, err := Hex[uint64](nil, l); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
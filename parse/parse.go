@@ -1,32 +1,82 @@
 // package parse is a small parser combinator library.
+//
+// The combinators and the basic parsers work on any Input, bytes or a string,
+// and so do Ref and Lazy, Memoise and RunMemo, Recover, Regexp and
+// Expression. Where a name has to say which, like Literal and LiteralOf, the
+// shorter one is for bytes. The rest only reads bytes: Format and Decoder
+// share their decoding with the code Decoder generates, which works on bytes;
+// Stream and Lines get bytes from an io.Reader; and Grammar's Nodes and
+// errors are built from bytes, as is the ready-made Arithmetic. To run one of
+// those over a string, convert it with []byte(s).
 package parse
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"strconv"
-	"unicode"
-	"unicode/utf8"
-
-	"golang.org/x/exp/constraints"
 )
 
-// ParseResult the result from running a parser function.
-type ParseResult[A any] struct {
+// Input is what parsers can read: bytes or a string. Parsers never copy
+// their input, so anything they return that's made of it, like the result of
+// Span, is a piece of the original.
+type Input interface {
+	~[]byte | ~string
+}
+
+// ResultOf is the result from running a parser function over input of type
+// In.
+type ResultOf[In Input, A any] struct {
 	result    A
-	remainder []byte
+	remainder In
 }
 
-// Parser is a function that extracts a value and moves the input along.
+// ParserOf is a function that extracts a value and moves the input along.
+// Most of the combinators work for any type of input, which they take from
+// the parsers they're given. s is the state of the run the parser is part of,
+// which it must pass on to any parsers it calls; it may be nil.
 // TODO: might need to be a struct for better error messages.
-type Parser[A any] func([]byte) (ParseResult[A], error)
+type ParserOf[In Input, A any] func(s *State, input In) (ResultOf[In, A], error)
+
+// State is what a single run of a parser keeps track of as it goes, so that
+// the parsers themselves don't have any and can be run concurrently: which
+// Refs are in progress where, and the cache of RunMemo or the trace of
+// RunTrace. It is created when it's needed, so Run starts with nil.
+//
+// Every parser in a run is given a piece of the same input that goes all the
+// way to its end, so how much input is left identifies a position in it.
+type State struct {
+	length int // of the whole input, if it's known
+	active map[activeRef]bool
+	memo   *memoRun
+	trace  *Trace
+}
+
+// newState returns the State for a run over input.
+func newState[In Input](input In) *State {
+	return &State{length: len(input)}
+}
+
+// offset returns how far into the whole input a parser that has remaining
+// bytes of it left is.
+func (s *State) offset(remaining int) int {
+	return s.length - remaining
+}
+
+// ParseResult the result from running a parser function.
+type ParseResult[A any] = ResultOf[[]byte, A]
+
+// Parser is a parser over bytes, which is what most of this package reads.
+type Parser[A any] = ParserOf[[]byte, A]
 
 // Run runs the parser on an input, returning the result. It is not an error if
 // the parser does not consume the entire input, but anything left over is not
 // returned.
-func Run[A any](p Parser[A], input []byte) (A, error) {
-	result, err := p(input)
+func Run[In Input, A any](p ParserOf[In, A], input In) (A, error) {
+	return runWith(nil, p, input)
+}
+
+// runWith is Run with the State s.
+func runWith[In Input, A any](s *State, p ParserOf[In, A], input In) (A, error) {
+	result, err := p(s, input)
 	if err != nil {
 		var a A
 		return a, err
@@ -36,13 +86,13 @@ func Run[A any](p Parser[A], input []byte) (A, error) {
 
 // Apply returns a new paresr that runs the first parser, then applies the
 // provided mapping function to its results (if it succeeds).
-func Apply[A, B any](p Parser[A], f func(A) B) Parser[B] {
-	return func(input []byte) (ParseResult[B], error) {
-		r, err := p(input)
+func Apply[In Input, A, B any](p ParserOf[In, A], f func(A) B) ParserOf[In, B] {
+	return func(s *State, input In) (ResultOf[In, B], error) {
+		r, err := p(s, input)
 		if err != nil {
-			return ParseResult[B]{}, err
+			return ResultOf[In, B]{}, err
 		}
-		return ParseResult[B]{
+		return ResultOf[In, B]{
 			result:    f(r.result),
 			remainder: r.remainder,
 		}, nil
@@ -51,35 +101,42 @@ func Apply[A, B any](p Parser[A], f func(A) B) Parser[B] {
 
 // Many returns a parser that applies the given parser repeatedly until it
 // fails. This may be zero times, so the returned parser itself never fails.
-func Many[A any](p Parser[A]) Parser[[]A] {
-	return func(input []byte) (ParseResult[[]A], error) {
+func Many[In Input, A any](p ParserOf[In, A]) ParserOf[In, []A] {
+	return func(s *State, input In) (ResultOf[In, []A], error) {
 		var results []A
 		for {
-			r, err := p(input)
-			if err != nil {
+			r, err := p(s, input)
+			if fatal(err) {
+				return ResultOf[In, []A]{}, err
+			}
+			if err != nil || len(r.remainder) == len(input) {
+				// Stop if it's not going anywhere, or we'd be
+				// here forever.
 				break
 			}
 			results = append(results, r.result)
 			input = r.remainder
 		}
-		return ParseResult[[]A]{
+		return ResultOf[In, []A]{
 			result:    results,
 			remainder: input,
 		}, nil
 	}
 }
 
+var errNoneFound = errors.New("Some: could not parse at least once")
+
 // Some returns a parser that applies the given parser as many times as it can,
 // and errors if it can not apply it at least once.
-func Some[A any](p Parser[A]) Parser[[]A] {
-	return func(input []byte) (ParseResult[[]A], error) {
-		r, err := Many(p)(input)
+func Some[In Input, A any](p ParserOf[In, A]) ParserOf[In, []A] {
+	return func(s *State, input In) (ResultOf[In, []A], error) {
+		r, err := Many(p)(s, input)
 		if err != nil {
 			// Not actual possible.
-			return ParseResult[[]A]{}, err
+			return ResultOf[In, []A]{}, err
 		}
 		if len(r.result) == 0 {
-			return ParseResult[[]A]{}, errors.New("Some: could not parse at least once")
+			return ResultOf[In, []A]{}, errNoneFound
 		}
 		return r, nil
 	}
@@ -87,21 +144,21 @@ func Some[A any](p Parser[A]) Parser[[]A] {
 
 // Between returns a parser that runs the three provided parsers in turn,
 // returning the middle one.
-func Between[A, B, C any](a Parser[A], b Parser[B], c Parser[C]) Parser[B] {
-	return func(input []byte) (ParseResult[B], error) {
-		aResult, err := a(input)
+func Between[In Input, A, B, C any](a ParserOf[In, A], b ParserOf[In, B], c ParserOf[In, C]) ParserOf[In, B] {
+	return func(s *State, input In) (ResultOf[In, B], error) {
+		aResult, err := a(s, input)
 		if err != nil {
-			return ParseResult[B]{}, err
+			return ResultOf[In, B]{}, err
 		}
-		result, err := b(aResult.remainder)
+		result, err := b(s, aResult.remainder)
 		if err != nil {
-			return ParseResult[B]{}, err
+			return ResultOf[In, B]{}, err
 		}
-		cResult, err := c(result.remainder)
+		cResult, err := c(s, result.remainder)
 		if err != nil {
-			return ParseResult[B]{}, err
+			return ResultOf[In, B]{}, err
 		}
-		return ParseResult[B]{
+		return ResultOf[In, B]{
 			result:    result.result,
 			remainder: cResult.remainder,
 		}, nil
@@ -110,30 +167,66 @@ func Between[A, B, C any](a Parser[A], b Parser[B], c Parser[C]) Parser[B] {
 
 // SepBy returns a parser that runs the first provided parser as many times as
 // it can, as long as each successful invocation is separated by a successful
-// invocation of the second parser.
-func SepBy[A, B any](a Parser[A], b Parser[B]) Parser[[]A] {
-	return func(input []byte) (ParseResult[[]A], error) {
-		var results []A
+// invocation of the second parser. It must succeed at least once. A separator
+// that isn't followed by another successful invocation is left unconsumed, so
+// a trailing newline doesn't upset a list of lines.
+func SepBy[In Input, A, B any](a ParserOf[In, A], b ParserOf[In, B]) ParserOf[In, []A] {
+	return func(s *State, input In) (ResultOf[In, []A], error) {
+		aResult, err := a(s, input)
+		if err != nil {
+			return ResultOf[In, []A]{}, err
+		}
+		results := []A{aResult.result}
+		input = aResult.remainder
 		for {
-			aResult, err := a(input)
+			bResult, err := b(s, input)
+			if fatal(err) {
+				return ResultOf[In, []A]{}, err
+			}
 			if err != nil {
-				return ParseResult[[]A]{}, err
+				break
+			}
+			aResult, err := a(s, bResult.remainder)
+			if fatal(err) {
+				return ResultOf[In, []A]{}, err
 			}
-			input = aResult.remainder
-
-			bResult, err := b(input)
 			if err != nil {
 				break
 			}
-			input = bResult.remainder
+			results = append(results, aResult.result)
+			input = aResult.remainder
 		}
-		return ParseResult[[]A]{
+		return ResultOf[In, []A]{
 			result:    results,
 			remainder: input,
 		}, nil
 	}
 }
 
+// Or returns a parser that tries each of the provided parsers in turn on the
+// same input, returning the result of the first one that succeeds. If none of
+// them do, it returns the error from the last one.
+//
+// Like all of the combinators that try alternatives, Or gives up straight
+// away on errors that mean the grammar itself is broken, such as
+// ErrLeftRecursion.
+func Or[In Input, A any](ps ...ParserOf[In, A]) ParserOf[In, A] {
+	return func(s *State, input In) (ResultOf[In, A], error) {
+		err := errors.New("Or: no parsers")
+		for _, p := range ps {
+			var r ResultOf[In, A]
+			r, err = p(s, input)
+			if err == nil {
+				return r, nil
+			}
+			if fatal(err) {
+				break
+			}
+		}
+		return ResultOf[In, A]{}, err
+	}
+}
+
 // Pair is a pair of values.
 type Pair[A, B any] struct {
 	First  A
@@ -142,18 +235,18 @@ type Pair[A, B any] struct {
 
 // Seq returns a parser that runs the two provided parsers in sequence and
 // returns both results.
-func Seq[A, B any](a Parser[A], b Parser[B]) Parser[Pair[A, B]] {
-	return func(input []byte) (ParseResult[Pair[A, B]], error) {
-		aResult, err := a(input)
+func Seq[In Input, A, B any](a ParserOf[In, A], b ParserOf[In, B]) ParserOf[In, Pair[A, B]] {
+	return func(s *State, input In) (ResultOf[In, Pair[A, B]], error) {
+		aResult, err := a(s, input)
 		if err != nil {
-			return ParseResult[Pair[A, B]]{}, err
+			return ResultOf[In, Pair[A, B]]{}, err
 		}
-		bResult, err := b(aResult.remainder)
+		bResult, err := b(s, aResult.remainder)
 		if err != nil {
-			return ParseResult[Pair[A, B]]{}, err
+			return ResultOf[In, Pair[A, B]]{}, err
 		}
 
-		return ParseResult[Pair[A, B]]{
+		return ResultOf[In, Pair[A, B]]{
 			result: Pair[A, B]{
 				First:  aResult.result,
 				Second: bResult.result,
@@ -165,67 +258,168 @@ func Seq[A, B any](a Parser[A], b Parser[B]) Parser[Pair[A, B]] {
 
 // SeqL returns a parser that runs a then b, and yields the result of parser
 // a.
-func SeqL[A, B any](a Parser[A], b Parser[B]) Parser[A] {
+func SeqL[In Input, A, B any](a ParserOf[In, A], b ParserOf[In, B]) ParserOf[In, A] {
 	return Apply(Seq(a, b), func(p Pair[A, B]) A { return p.First })
 }
 
 // SeqR returns a parser that runs a then b, and yields the result of parser
 // b.
-func SeqR[A, B any](a Parser[A], b Parser[B]) Parser[B] {
+func SeqR[In Input, A, B any](a ParserOf[In, A], b ParserOf[In, B]) ParserOf[In, B] {
 	return Apply(Seq(a, b), func(p Pair[A, B]) B { return p.Second })
 }
 
 // Literal returns a parser that parses an exact string.
 func Literal(s string) Parser[string] {
-	return func(input []byte) (ParseResult[string], error) {
-		if !bytes.HasPrefix(input, []byte(s)) {
+	return LiteralOf[[]byte](s)
+}
+
+// LiteralOf is Literal for any type of input.
+func LiteralOf[In Input](s string) ParserOf[In, string] {
+	return func(_ *State, input In) (ResultOf[In, string], error) {
+		if len(input) < len(s) && string(input) == s[:len(input)] {
+			return endedEarly(s, input)
+		}
+		if len(input) < len(s) || string(input[:len(s)]) != s {
 			return unexpectedError(s, input)
 		}
-		return ParseResult[string]{
+		return ResultOf[In, string]{
 			result:    s,
-			remainder: input[len(input):],
+			remainder: input[len(s):],
 		}, nil
 	}
 }
 
 // Byte returns a parser that parses a single exact byte.
 func Byte(b byte) Parser[byte] {
-	return func(input []byte) (ParseResult[byte], error) {
+	return ByteOf[[]byte](b)
+}
+
+// ByteOf is Byte for any type of input.
+func ByteOf[In Input](b byte) ParserOf[In, byte] {
+	return func(_ *State, input In) (ResultOf[In, byte], error) {
 		if len(input) == 0 || input[0] != b {
 			return unexpectedError(b, input)
 		}
-		return ParseResult[byte]{
+		return ResultOf[In, byte]{
 			result:    b,
 			remainder: input[1:],
 		}, nil
 	}
 }
 
-// Uint is a parser that parses a single unsigned integer.
-func Uint[U constraints.Unsigned](input []byte) (ParseResult[U], error) {
-	end := 0
-	for {
-		r, w := utf8.DecodeRune(input[end:])
-		if !unicode.IsDigit(r) {
-			break
+// ApplyErr is like Apply, but the mapping function can fail, in which case so
+// does the parser.
+func ApplyErr[In Input, A, B any](p ParserOf[In, A], f func(A) (B, error)) ParserOf[In, B] {
+	return func(s *State, input In) (ResultOf[In, B], error) {
+		r, err := p(s, input)
+		if err != nil {
+			return ResultOf[In, B]{}, err
+		}
+		b, err := f(r.result)
+		if err != nil {
+			return ResultOf[In, B]{}, err
 		}
-		end += w
+		return ResultOf[In, B]{
+			result:    b,
+			remainder: r.remainder,
+		}, nil
 	}
-	// TODO: not this temporary string it's pretty silly
-	result, err := strconv.ParseUint(string(input[:end]), 10, 64)
-	if err != nil {
-		return ParseResult[U]{}, err
+}
+
+// Span returns a parser that runs p and returns the piece of the input that
+// it consumed, rather than its result, without copying it.
+func Span[In Input, A any](p ParserOf[In, A]) ParserOf[In, In] {
+	return func(s *State, input In) (ResultOf[In, In], error) {
+		r, err := p(s, input)
+		if err != nil {
+			return ResultOf[In, In]{}, err
+		}
+		return ResultOf[In, In]{
+			result:    input[:len(input)-len(r.remainder)],
+			remainder: r.remainder,
+		}, nil
+	}
+}
+
+// Pure returns a parser that always succeeds with a, without consuming any
+// input.
+func Pure[A any](a A) Parser[A] {
+	return PureOf[[]byte](a)
+}
+
+// PureOf is Pure for any type of input.
+func PureOf[In Input, A any](a A) ParserOf[In, A] {
+	return func(_ *State, input In) (ResultOf[In, A], error) {
+		return ResultOf[In, A]{
+			result:    a,
+			remainder: input,
+		}, nil
 	}
-	return ParseResult[U]{
-		result:    U(result),
-		remainder: input[end:],
-	}, nil
 }
 
-func unexpectedError[A any](want A, input []byte) (ParseResult[A], error) {
+// Spaces is a parser that skips over any spaces or tabs. It never fails.
+func Spaces(s *State, input []byte) (ParseResult[struct{}], error) {
+	return SpacesOf(s, input)
+}
+
+// SpacesOf is Spaces for any type of input.
+func SpacesOf[In Input](_ *State, input In) (ResultOf[In, struct{}], error) {
+	end := 0
+	for end < len(input) && (input[end] == ' ' || input[end] == '\t') {
+		end++
+	}
+	return ResultOf[In, struct{}]{remainder: input[end:]}, nil
+}
+
+// End is a parser that only succeeds at the end of the input.
+func End(s *State, input []byte) (ParseResult[struct{}], error) {
+	return EndOf(s, input)
+}
+
+// EndOf is End for any type of input.
+func EndOf[In Input](_ *State, input In) (ResultOf[In, struct{}], error) {
+	if len(input) > 0 {
+		_, err := unexpectedError("end of input", input)
+		return ResultOf[In, struct{}]{}, err
+	}
+	return ResultOf[In, struct{}]{remainder: input}, nil
+}
+
+func unexpectedError[A any, In Input](want A, input In) (ResultOf[In, A], error) {
 	// TODO: %q if A is stringy enough?
+	return ResultOf[In, A]{}, &unexpected{
+		want:      fmt.Sprint(want),
+		found:     string(truncate(input)),
+		remaining: len(input),
+	}
+}
+
+// endedEarly is unexpectedError for input that ran out partway through what
+// was wanted, so that Stream knows more of it might match.
+func endedEarly[A any, In Input](want A, input In) (ResultOf[In, A], error) {
+	r, err := unexpectedError(want, input)
+	err.(*unexpected).short = true
+	return r, err
+}
+
+// unexpected is the error returned when a parser doesn't find what it wants.
+// It remembers how much input was left, so that Recover and Lines can work
+// out where it happened.
+type unexpected struct {
+	want      string
+	found     string // copied, because the input might be reused
+	remaining int
+	short     bool // the input ended before it could match
+}
+
+func (u *unexpected) Error() string {
+	return fmt.Sprintf("expected %s, found: %q", u.want, u.found)
+}
+
+// truncate shortens input for error messages.
+func truncate[In Input](input In) In {
 	if len(input) > 25 {
-		input = input[:25]
+		return input[:25]
 	}
-	return ParseResult[A]{}, fmt.Errorf("expected %v, found: %q", want, input)
+	return input
 }
@@ -0,0 +1,48 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestStringInput(t *testing.T) {
+	var (
+		number = UintOf[uint64, string]
+		list   = SeqL(SepBy(SeqR(SpacesOf[string], number), ByteOf[string](',')), EndOf[string])
+	)
+	got, err := Run(list, "1, 2,3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := Run(list, "1,2,x"); err == nil {
+		t.Error("1,2,x: no error")
+	}
+
+	input := "key=value"
+	key, err := Run(SeqL(Span(Some(Or(ByteOf[string]('k'), ByteOf[string]('e'), ByteOf[string]('y')))), LiteralOf[string]("=")), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "key" || unsafe.StringData(key) != unsafe.StringData(input) {
+		t.Errorf("got %q, want a piece of the input", key)
+	}
+}
+
+func TestNoCopies(t *testing.T) {
+	var (
+		str   = SeqR(LiteralOf[string]("x="), IntOf[int, string])
+		bytes = SeqR(Literal("x="), Int[int])
+		s     = "x=-123"
+		b     = []byte(s)
+	)
+	if allocs := testing.AllocsPerRun(100, func() { Run(str, s) }); allocs != 0 {
+		t.Errorf("string input: %v allocations, want none", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { Run(bytes, b) }); allocs != 0 {
+		t.Errorf("byte input: %v allocations, want none", allocs)
+	}
+}
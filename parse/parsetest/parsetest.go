@@ -0,0 +1,132 @@
+// Package parsetest has helpers for testing code generated from parsers
+// against the parsers it came from, and Syntaxes against the inputs they
+// describe.
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/pfcm/aoc25/parse"
+)
+
+// Generated checks that file holds what d.Generate writes for a function
+// called name in package pkg, which is what go generate leaves there.
+func Generated[T any](t *testing.T, d *parse.Decoder[T], pkg, name, file string) {
+	t.Helper()
+	var got bytes.Buffer
+	if err := d.Generate(&got, pkg, name); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("%s is out of date, run go generate", file)
+	}
+}
+
+// Readers compares a reader that uses generated code with one that uses
+// what it was generated from.
+type Readers[T any] struct {
+	Got, Want func([]byte) (T, error)
+
+	// Breaks are inserted one at a time into each input, at each of the
+	// offsets given by At, or at a few places spread over the whole input
+	// if At is nil.
+	Breaks []byte
+	At     func(input []byte) []int
+
+	// Extra gives any other broken versions of the input to try.
+	Extra func(input []byte) [][]byte
+}
+
+// Test checks that the readers give the same results and the same errors for
+// each of the files and the broken versions of them. It skips the test if a
+// file isn't there, as the puzzle inputs aren't checked in.
+func (r Readers[T]) Test(t *testing.T, files ...string) {
+	t.Helper()
+	for _, name := range files {
+		input, err := os.ReadFile(name)
+		if err != nil {
+			t.Skip(err)
+		}
+		compare := func(input []byte) {
+			t.Helper()
+			want, wantErr := r.Want(input)
+			got, gotErr := r.Got(input)
+			if errString(gotErr) != errString(wantErr) {
+				t.Fatalf("%s: got error %v, want %v", name, gotErr, wantErr)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: got %v, want %v", name, got, want)
+			}
+		}
+		compare(input)
+		at := []int{0, 1, len(input) / 3, len(input) / 2, len(input) - 2}
+		if r.At != nil {
+			at = r.At(input)
+		}
+		for _, i := range at {
+			for _, b := range r.Breaks {
+				broken := append(input[:i:i], b)
+				compare(append(broken, input[i:]...))
+			}
+		}
+		if r.Extra != nil {
+			for _, extra := range r.Extra(input) {
+				compare(extra)
+			}
+		}
+	}
+}
+
+// RoundTrip checks that s reads each of the files to the same value as read
+// does, prints that value back out as exactly the file it came from, and
+// reads the printout to the same value again. It skips the test if a file
+// isn't there, as the puzzle inputs aren't checked in.
+func RoundTrip[A any](t *testing.T, s parse.Syntax[A], read func([]byte) (A, error), files ...string) {
+	t.Helper()
+	whole := parse.SeqL(s.Parser, parse.End)
+	for _, name := range files {
+		input, err := os.ReadFile(name)
+		if err != nil {
+			t.Skip(err)
+		}
+		want, err := read(input)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, err := parse.Run(whole, input)
+		if err != nil {
+			t.Fatalf("%s: parsing: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: parsed %v, want %v", name, got, want)
+		}
+		printed, err := s.Bytes(got)
+		if err != nil {
+			t.Fatalf("%s: printing: %v", name, err)
+		}
+		if !bytes.Equal(printed, input) {
+			t.Fatalf("%s: printed %q, want the file's %q", name, printed, input)
+		}
+		again, err := parse.Run(whole, printed)
+		if err != nil {
+			t.Fatalf("%s: parsing the printout: %v", name, err)
+		}
+		if !reflect.DeepEqual(again, got) {
+			t.Fatalf("%s: parsed the printout as %v, want %v", name, again, got)
+		}
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,107 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ParseError is an error at a particular place in the input.
+type ParseError struct {
+	Offset       int // in bytes, from the start of the input
+	Line, Column int // both starting at 1, Column is in bytes
+	Err          error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// newParseError works out where in input err happened. start is the offset of
+// the thing that failed, which is used if err doesn't say any more precisely.
+func newParseError[In Input](input In, start int, err error) *ParseError {
+	offset := start
+	var u *unexpected
+	if errors.As(err, &u) && len(input)-u.remaining >= start {
+		offset = len(input) - u.remaining
+	}
+	line, lineStart := 1, 0
+	for i := range offset {
+		if input[i] == '\n' {
+			line, lineStart = line+1, i+1
+		}
+	}
+	return &ParseError{
+		Offset: offset,
+		Line:   line,
+		Column: offset - lineStart + 1,
+		Err:    err,
+	}
+}
+
+// Recovered is the result of a parser that can recover from errors: all of the
+// values that were parsed, and errors for anything that couldn't be.
+type Recovered[A any] struct {
+	Values []A
+	Errors []*ParseError
+}
+
+// Err returns all of the errors joined together, or nil if there weren't any.
+func (r Recovered[A]) Err() error {
+	errs := make([]error, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = e
+	}
+	return errors.Join(errs...)
+}
+
+// Recover returns a parser that runs p repeatedly until the input runs out.
+// When p fails the error is recorded, the input is skipped up to and including
+// the next place sync matches, and then it carries on. For a line based input
+// sync is usually Byte('\n'), and p should consume the newline at the end of
+// each line as well.
+//
+// The returned parser only fails on errors that mean the grammar is broken,
+// such as ErrLeftRecursion; positions in the errors it records are relative to
+// the input it is given.
+func Recover[In Input, A, B any](p ParserOf[In, A], sync ParserOf[In, B]) ParserOf[In, Recovered[A]] {
+	return func(s *State, input In) (ResultOf[In, Recovered[A]], error) {
+		var (
+			result Recovered[A]
+			rest   = input
+		)
+		for len(rest) > 0 {
+			start := len(input) - len(rest)
+			r, err := p(s, rest)
+			if fatal(err) {
+				return ResultOf[In, Recovered[A]]{}, err
+			}
+			if err == nil && len(r.remainder) == len(rest) {
+				err = errors.New("parser consumed no input")
+			}
+			if err == nil {
+				result.Values = append(result.Values, r.result)
+				rest = r.remainder
+				continue
+			}
+			result.Errors = append(result.Errors, newParseError(input, start, err))
+			rest = skipPast(s, rest, sync)
+		}
+		return ResultOf[In, Recovered[A]]{
+			result:    result,
+			remainder: rest,
+		}, nil
+	}
+}
+
+// skipPast returns what's left of input after the first match of sync, or
+// nothing if it never matches.
+func skipPast[In Input, B any](s *State, input In, sync ParserOf[In, B]) In {
+	for i := range len(input) {
+		if r, err := sync(s, input[i:]); err == nil && len(r.remainder) < len(input) {
+			return r.remainder
+		}
+	}
+	return input[len(input):]
+}
@@ -0,0 +1,70 @@
+package parse
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	input := []byte(`1-2
+3-x
+5-6
+7_8
+9-10
+-11
+12-13
+14-15-
+16
+17-18`)
+	var (
+		line = SeqL(Seq(SeqL(Uint[uint64], Byte('-')), Uint[uint64]), Or(Byte('\n'), Pure[byte](0)))
+		p    = Recover(line, Byte('\n'))
+	)
+	got, err := Run(p, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "14-15" parses fine, it's the "-" after it that doesn't.
+	if len(got.Values) != 6 {
+		t.Errorf("got %d values, want 6: %v", len(got.Values), got.Values)
+	}
+	want := []struct{ line, column int }{
+		{2, 3},
+		{4, 2},
+		{6, 1},
+		{8, 6},
+		{9, 3},
+	}
+	if len(got.Errors) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(got.Errors), len(want), got.Err())
+	}
+	for i, w := range want {
+		if e := got.Errors[i]; e.Line != w.line || e.Column != w.column {
+			t.Errorf("error %d: got %v, want line %d, column %d", i, e, w.line, w.column)
+		}
+	}
+}
+
+func TestRecoverString(t *testing.T) {
+	var (
+		line = SeqL(UintOf[uint64, string], Or(ByteOf[string]('\n'), PureOf[string, byte](0)))
+		p    = Recover(line, ByteOf[string]('\n'))
+	)
+	got, err := Run(p, "1\nx\n3\n4y\n5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "4" parses fine, it's the "y" after it that doesn't.
+	if want := []uint64{1, 3, 4, 5}; !slices.Equal(got.Values, want) {
+		t.Errorf("got values %v, want %v", got.Values, want)
+	}
+	want := []struct{ line, column int }{{2, 1}, {4, 2}}
+	if len(got.Errors) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(got.Errors), len(want), got.Err())
+	}
+	for i, w := range want {
+		if e := got.Errors[i]; e.Line != w.line || e.Column != w.column {
+			t.Errorf("error %d: got %v, want line %d, column %d", i, e, w.line, w.column)
+		}
+	}
+}
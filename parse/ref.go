@@ -0,0 +1,110 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrLeftRecursion is returned when a recursive parser calls itself again
+// without having consumed any input, which would otherwise recurse forever.
+var ErrLeftRecursion = errors.New("left recursion")
+
+// fatal reports whether err means the grammar is broken, rather than that the
+// input didn't match. Combinators that try alternatives should pass these on
+// instead of moving on to the next alternative.
+func fatal(err error) bool {
+	return errors.Is(err, ErrLeftRecursion)
+}
+
+// Ref is a reference to a parser that can be used before it's been defined,
+// so that a grammar can refer to itself:
+//
+//	type tree []tree
+//	t := NewRef[tree]()
+//	t.Set(Apply(
+//		Between(Byte('['), SepBy(t.Parser(), Byte(',')), Byte(']')),
+//		func(children []tree) tree { return children },
+//	))
+//
+// If a Ref is re-entered at the same point in the input before the first call
+// has returned then the grammar is left recursive, and it fails with
+// ErrLeftRecursion instead of overflowing the stack. The calls in progress are
+// kept in the State of each run, so the same Ref can be used by any number of
+// runs at once.
+type Ref[A any] = RefOf[[]byte, A]
+
+// RefOf is a Ref for any type of input.
+type RefOf[In Input, A any] struct {
+	p atomic.Pointer[ParserOf[In, A]]
+}
+
+// activeRef is a Ref that's in progress, and how much input it was given.
+type activeRef struct {
+	ref       any
+	remaining int
+}
+
+// NewRef returns a new Ref, which must be Set before it's used.
+func NewRef[A any]() *Ref[A] {
+	return NewRefOf[[]byte, A]()
+}
+
+// NewRefOf returns a new RefOf, which must be Set before it's used.
+func NewRefOf[In Input, A any]() *RefOf[In, A] {
+	return &RefOf[In, A]{}
+}
+
+// Set sets the parser that r refers to. It's safe to call at any time, but
+// runs that are already going may carry on with the old parser, so it should
+// normally be called once before r's parser is first used.
+func (r *RefOf[In, A]) Set(p ParserOf[In, A]) {
+	r.p.Store(&p)
+}
+
+// Parser returns a parser that runs whatever r has been set to.
+func (r *RefOf[In, A]) Parser() ParserOf[In, A] {
+	return func(s *State, input In) (ResultOf[In, A], error) {
+		p := r.p.Load()
+		if p == nil {
+			return ResultOf[In, A]{}, errors.New("parse: Ref used before Set")
+		}
+		if s == nil {
+			s = &State{}
+		}
+		if s.active == nil {
+			s.active = make(map[activeRef]bool)
+		}
+		key := activeRef{ref: r, remaining: len(input)}
+		if s.active[key] {
+			return ResultOf[In, A]{}, fmt.Errorf("%w at %q", ErrLeftRecursion, truncate(input))
+		}
+		s.active[key] = true
+		defer delete(s.active, key)
+		return (*p)(s, input)
+	}
+}
+
+// Lazy returns a parser that calls f to get the parser to run the first time
+// it's needed, so that a parser can refer to a variable that hasn't been
+// assigned yet:
+//
+//	var t Parser[tree]
+//	t = Apply(
+//		Between(Byte('['), SepBy(Lazy(func() Parser[tree] { return t }), Byte(',')), Byte(']')),
+//		func(children []tree) tree { return children },
+//	)
+//
+// It detects left recursion in the same way as Ref.
+func Lazy[In Input, A any](f func() ParserOf[In, A]) ParserOf[In, A] {
+	var (
+		once sync.Once
+		r    = NewRefOf[In, A]()
+	)
+	p := r.Parser()
+	return func(s *State, input In) (ResultOf[In, A], error) {
+		once.Do(func() { r.Set(f()) })
+		return p(s, input)
+	}
+}
@@ -0,0 +1,105 @@
+package parse
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// tree is a nested list of numbers, like [1,[2,[]],3].
+type tree struct {
+	value    uint64
+	children []tree
+}
+
+func TestRef(t *testing.T) {
+	r := NewRef[tree]()
+	list := Apply(
+		Between(Byte('['), Or(SepBy(r.Parser(), Byte(',')), Pure[[]tree](nil)), Byte(']')),
+		func(children []tree) tree { return tree{children: children} },
+	)
+	r.Set(Or(list, Apply(Uint[uint64], func(u uint64) tree { return tree{value: u} })))
+
+	got, err := Run(r.Parser(), []byte("[1,[2,[]],3]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := tree{children: []tree{
+		{value: 1},
+		{children: []tree{{value: 2}, {}}},
+		{value: 3},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := Run(r.Parser(), []byte("[1,[2,]")); err == nil {
+		t.Error("unbalanced brackets: no error")
+	}
+}
+
+func TestRefString(t *testing.T) {
+	// sum = number '+' sum | number, over a string.
+	sum := NewRefOf[string, uint64]()
+	sum.Set(Or(
+		Apply(
+			Seq(SeqL(UintOf[uint64, string], ByteOf[string]('+')), sum.Parser()),
+			func(p Pair[uint64, uint64]) uint64 { return p.First + p.Second },
+		),
+		UintOf[uint64, string],
+	))
+	if got, err := Run(sum.Parser(), "1+2+3"); err != nil || got != 6 {
+		t.Errorf("got %d, %v; want 6", got, err)
+	}
+
+	var left ParserOf[string, uint64]
+	left = Or(
+		Apply(
+			Seq(SeqL(Lazy(func() ParserOf[string, uint64] { return left }), ByteOf[string]('+')), UintOf[uint64, string]),
+			func(p Pair[uint64, uint64]) uint64 { return p.First + p.Second },
+		),
+		UintOf[uint64, string],
+	)
+	if _, err := Run(left, "1+2"); !errors.Is(err, ErrLeftRecursion) {
+		t.Errorf("got error %v, want %v", err, ErrLeftRecursion)
+	}
+}
+
+func TestLazyLeftRecursion(t *testing.T) {
+	// sum = sum '+' number | number
+	var sum Parser[uint64]
+	sum = Or(
+		Apply(
+			Seq(SeqL(Lazy(func() Parser[uint64] { return sum }), Byte('+')), Uint[uint64]),
+			func(p Pair[uint64, uint64]) uint64 { return p.First + p.Second },
+		),
+		Uint[uint64],
+	)
+	if _, err := Run(sum, []byte("1+2")); !errors.Is(err, ErrLeftRecursion) {
+		t.Errorf("got error %v, want %v", err, ErrLeftRecursion)
+	}
+}
+
+func TestRefConcurrent(t *testing.T) {
+	// Arithmetic is a single Ref shared by everything that uses it, and
+	// these all parse the same slice at the same time.
+	input := []byte("1+2*(3+4)*(5+6)-7")
+	var wg sync.WaitGroup
+	for range 64 {
+		wg.Go(func() {
+			for range 100 {
+				a, err := Run(Arithmetic, input)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if got := a.String(); got != "((1 + ((2 * (3 + 4)) * (5 + 6))) - 7)" {
+					t.Errorf("got %s", got)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,125 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// Regexp returns a parser that matches a regular expression at the start of
+// its input and consumes the match. If T is a struct, each named group in the
+// expression is decoded into the field of T with the same name, in the same
+// way as the captures in a Format; groups that don't take part in the match
+// leave their field alone. Otherwise the whole match is decoded into a T. It
+// panics if the expression is invalid, see CompileRegexp for a version that
+// doesn't.
+func Regexp[T any](pattern string) Parser[T] {
+	return RegexpOf[T, []byte](pattern)
+}
+
+// RegexpOf is Regexp for any type of input.
+func RegexpOf[T any, In Input](pattern string) ParserOf[In, T] {
+	p, err := CompileRegexpOf[T, In](pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// CompileRegexp is like Regexp, but returns an error if the expression is
+// invalid or doesn't fit T.
+func CompileRegexp[T any](pattern string) (Parser[T], error) {
+	return CompileRegexpOf[T, []byte](pattern)
+}
+
+// CompileRegexpOf is CompileRegexp for any type of input. The groups of a
+// match in a string are copied to decode them.
+func CompileRegexpOf[T any, In Input](pattern string) (ParserOf[In, T], error) {
+	re, err := regexp.Compile(`^(?:` + pattern + `)`)
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeFor[T]()
+
+	// groups are the decoders for each submatch, the whole match is
+	// group zero.
+	type group struct {
+		index  int // of the submatch
+		field  int // -1 for the whole value
+		name   string
+		decode decodeFunc
+	}
+	var groups []group
+	if t.Kind() == reflect.Struct {
+		for i, name := range re.SubexpNames() {
+			if name == "" {
+				continue
+			}
+			field, _, err := captureField(t, []segment{{capture: name}}, 0, "")
+			if err != nil {
+				return nil, err
+			}
+			decode, err := compileValue(field.Type, field.Tag, 0, "")
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			groups = append(groups, group{index: i, field: field.Index[0], name: name, decode: decode})
+		}
+	} else {
+		decode, err := compileValue(t, "", 0, "")
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group{field: -1, decode: decode})
+	}
+
+	return func(s *State, input In) (ResultOf[In, T], error) {
+		match := findSubmatchIndex(re, input)
+		if match == nil {
+			_, err := unexpectedError("match for /"+pattern+"/", input)
+			return ResultOf[In, T]{}, err
+		}
+		var result T
+		v := reflect.ValueOf(&result).Elem()
+		for _, g := range groups {
+			start, end := match[2*g.index], match[2*g.index+1]
+			if start < 0 {
+				continue
+			}
+			dest := v
+			if g.field >= 0 {
+				dest = v.Field(g.field)
+			}
+			rest, err := g.decode([]byte(input[start:end]), dest)
+			if err == nil && len(rest) > 0 {
+				_, err = unexpectedError("end of group", rest)
+			}
+			if err != nil {
+				// The group was parsed on its own, so anything
+				// after it needs counting to find where the
+				// error was.
+				var u *unexpected
+				if errors.As(err, &u) {
+					u.remaining += len(input) - end
+				}
+				if g.name != "" {
+					err = NewFieldError(g.name, err)
+				}
+				return ResultOf[In, T]{}, err
+			}
+		}
+		return ResultOf[In, T]{
+			result:    result,
+			remainder: input[match[1]:],
+		}, nil
+	}, nil
+}
+
+// findSubmatchIndex is re.FindSubmatchIndex for any type of input.
+func findSubmatchIndex[In Input](re *regexp.Regexp, input In) []int {
+	if b, ok := any(input).([]byte); ok {
+		return re.FindSubmatchIndex(b)
+	}
+	return re.FindStringSubmatchIndex(string(input))
+}
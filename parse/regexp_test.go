@@ -0,0 +1,52 @@
+package parse
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type regexpMove struct {
+	Count    int
+	From, To uint8
+	Tags     []string `sep:"|"`
+}
+
+func TestRegexp(t *testing.T) {
+	move := Regexp[regexpMove](`move (?P<count>-?\d+) from (?P<from>\d) to (?P<to>\d+)(?: \[(?P<tags>[a-z|]+)\])?`)
+	p := SepBy(move, Byte('\n'))
+
+	got, err := Run(p, []byte("move -3 from 1 to 2 [a|bc]\nmove 10 from 3 to 4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []regexpMove{
+		{Count: -3, From: 1, To: 2, Tags: []string{"a", "bc"}},
+		{Count: 10, From: 3, To: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	_, err = Run(move, []byte("move 1 from 9 to 999"))
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "to" {
+		t.Errorf("got error %v, want one for field to", err)
+	}
+	if _, err := Run(move, []byte(" move 1 from 2 to 3")); err == nil {
+		t.Error("match not at the start: no error")
+	}
+
+	word := Regexp[string](`[a-z]+`)
+	if got, err := Run(SeqR(Byte('<'), SeqL(word, Byte('>'))), []byte("<hello>")); err != nil || got != "hello" {
+		t.Errorf("got %q, %v; want hello", got, err)
+	}
+
+	moveString := RegexpOf[regexpMove, string](`move (?P<count>-?\d+) from (?P<from>\d) to (?P<to>\d+)`)
+	if got, err := Run(moveString, "move 5 from 1 to 2"); err != nil || !reflect.DeepEqual(got, regexpMove{Count: 5, From: 1, To: 2}) {
+		t.Errorf("string: got %+v, %v", got, err)
+	}
+	if _, err := Run(moveString, "move 5 from 1 to 999"); !errors.As(err, &fe) || fe.Field != "to" {
+		t.Errorf("string: got error %v, want one for field to", err)
+	}
+}
@@ -0,0 +1,154 @@
+package parse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// DefaultMaxRecord is the largest single record Stream and Lines will buffer.
+const DefaultMaxRecord = bufio.MaxScanTokenSize
+
+// ErrRecordTooLong is returned when a record does not fit in the buffer.
+var ErrRecordTooLong = errors.New("record too long")
+
+// Stream returns an iterator that applies p to the input read from r over and
+// over until the input is exhausted, yielding each result in turn. Only one
+// record is buffered at a time (up to DefaultMaxRecord bytes) so arbitrarily
+// large inputs can be parsed in constant memory.
+//
+// p should parse a single record, including any separator that follows it. If
+// p succeeds having consumed everything buffered so far, or fails because it
+// ran into the end of it, more input is read and it is tried again, so a
+// result is only yielded once p has stopped short of the end of the buffer or
+// the input has run out. Any other failure is yielded straight away with its
+// offset in the input. Iteration stops after the first error.
+func Stream[A any](r io.Reader, p Parser[A]) iter.Seq2[A, error] {
+	return StreamSize(r, p, DefaultMaxRecord)
+}
+
+// StreamSize is like Stream, but with a configurable maximum record size.
+func StreamSize[A any](r io.Reader, p Parser[A], maxRecord int) iter.Seq2[A, error] {
+	return func(yield func(A, error) bool) {
+		var (
+			buf        = make([]byte, min(4096, maxRecord))
+			start, end = 0, 0 // the unparsed input is buf[start:end]
+			offset     = 0    // of buf[start] in the whole input
+			eof        = false
+			zero       A
+		)
+		for start < end || !eof {
+			input := buf[start:end]
+			result, err := p(nil, input)
+			consumed := len(input) - len(result.remainder)
+			if err == nil && consumed == 0 && len(input) > 0 {
+				yield(zero, fmt.Errorf("offset %d: parser consumed no input", offset))
+				return
+			}
+			if err == nil && (consumed < len(input) || eof) {
+				if !yield(result.result, nil) {
+					return
+				}
+				start += consumed
+				offset += consumed
+				continue
+			}
+			if err != nil && (eof || !wantsMore(err)) {
+				yield(zero, fmt.Errorf("offset %d: %w", offset+failedAt(input, err), err))
+				return
+			}
+			// p either wants more than we have or might do, so read
+			// some more and try again.
+			if len(input) >= maxRecord {
+				yield(zero, fmt.Errorf("offset %d: %w", offset, ErrRecordTooLong))
+				return
+			}
+			if end == len(buf) {
+				if len(input) == len(buf) {
+					buf = make([]byte, min(2*len(buf), maxRecord))
+				}
+				copy(buf, input)
+				start, end = 0, len(input)
+			}
+			n, err := io.ReadAtLeast(r, buf[end:], 1)
+			end += n
+			switch err {
+			case nil:
+			case io.EOF:
+				eof = true
+			default:
+				yield(zero, err)
+				return
+			}
+		}
+	}
+}
+
+// wantsMore reports whether p failed because it reached the end of input,
+// either with nothing left or partway through something like a literal, in
+// which case it might succeed given more.
+func wantsMore(err error) bool {
+	var u *unexpected
+	return errors.As(err, &u) && (u.remaining == 0 || u.short)
+}
+
+// failedAt is the offset in input that err happened at, or 0 if it doesn't
+// say.
+func failedAt(input []byte, err error) int {
+	var u *unexpected
+	if errors.As(err, &u) && u.remaining <= len(input) {
+		return len(input) - u.remaining
+	}
+	return 0
+}
+
+// Lines returns an iterator that applies p to each line read from r, yielding
+// the results in order. Each line must be parsed in its entirety. Lines are
+// limited to DefaultMaxRecord bytes, and the slices p sees are only valid
+// until the next line is read.
+//
+// A line that can't be parsed yields a *ParseError, after which iteration
+// carries on with the next line so that every bad line can be reported.
+// Errors reading from r end the iteration.
+func Lines[A any](r io.Reader, p Parser[A]) iter.Seq2[A, error] {
+	return func(yield func(A, error) bool) {
+		var (
+			scan     = bufio.NewScanner(r)
+			offset   = 0
+			consumed = 0 // by the last line, including its ending
+			zero     A
+		)
+		// The lines have their "\n" or "\r\n" cut off, so keep track of
+		// how long they really were.
+		scan.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+			advance, token, err := bufio.ScanLines(data, atEOF)
+			if token != nil {
+				consumed = advance
+			}
+			return advance, token, err
+		})
+		for line := 1; scan.Scan(); line++ {
+			b := scan.Bytes()
+			result, err := p(nil, b)
+			if err == nil && len(result.remainder) > 0 {
+				_, err = unexpectedError("end of line", result.remainder)
+			}
+			if err != nil {
+				pe := newParseError(b, 0, err)
+				pe.Line = line
+				pe.Offset += offset
+				if !yield(zero, pe) {
+					return
+				}
+			} else if !yield(result.result, nil) {
+				return
+			}
+			offset += consumed
+		}
+		if err := scan.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
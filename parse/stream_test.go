@@ -0,0 +1,198 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// numbers is an io.Reader that generates "0\n1\n2\n..." forever.
+type numbers struct {
+	next    uint64
+	pending []byte
+}
+
+func (n *numbers) Read(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		if len(n.pending) == 0 {
+			n.pending = strconv.AppendUint(n.pending[:0], n.next, 10)
+			n.pending = append(n.pending, '\n')
+			n.next++
+		}
+		c := copy(p[written:], n.pending)
+		n.pending = n.pending[c:]
+		written += c
+	}
+	return written, nil
+}
+
+func TestStream(t *testing.T) {
+	const n = 100000
+	// A tiny maximum record size means this can only pass if the buffer
+	// is being reused.
+	r := io.LimitReader(&numbers{}, 1<<20)
+	want := uint64(0)
+	for got, err := range StreamSize(r, SeqL(Uint[uint64], Byte('\n')), 32) {
+		if err != nil {
+			// The limit is going to chop a number in half.
+			if want < n {
+				t.Fatalf("after %d records: %v", want, err)
+			}
+			break
+		}
+		if got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		want++
+	}
+}
+
+func TestStreamRecordTooLong(t *testing.T) {
+	r := strings.NewReader(strings.Repeat("1", 100) + "\n")
+	for _, err := range StreamSize(r, SeqL(Uint[uint64], Byte('\n')), 16) {
+		if !errors.Is(err, ErrRecordTooLong) {
+			t.Fatalf("got error %v, want %v", err, ErrRecordTooLong)
+		}
+		return
+	}
+	t.Fatal("no error")
+}
+
+func TestStreamBoundaries(t *testing.T) {
+	// With a small buffer the records end up split at every possible
+	// place, including partway through the literal and just after a sign.
+	const n = 1000
+	for _, c := range []struct {
+		name   string
+		record string
+		p      Parser[string]
+	}{
+		{"literal", "abcdefgh\n", SeqL(Literal("abcdefgh"), Byte('\n'))},
+		{"sign", "-1234\n", Apply(SeqL(Int[int], Byte('\n')), strconv.Itoa)},
+	} {
+		input := strings.Repeat(c.record, n)
+		for _, size := range []int{16, DefaultMaxRecord} {
+			got := 0
+			for s, err := range StreamSize(strings.NewReader(input), c.p, size) {
+				if err != nil {
+					t.Fatalf("%s, buffer %d: after %d records: %v", c.name, size, got, err)
+				}
+				if want := strings.TrimSuffix(c.record, "\n"); s != want {
+					t.Fatalf("%s, buffer %d: got %q, want %q", c.name, size, s, want)
+				}
+				got++
+			}
+			if got != n {
+				t.Errorf("%s, buffer %d: got %d records, want %d", c.name, size, got, n)
+			}
+		}
+	}
+}
+
+func TestStreamBadRecord(t *testing.T) {
+	// The bad record should be reported where it is, without reading the
+	// rest of the stream looking for more of it.
+	r := &countingReader{r: io.MultiReader(
+		strings.NewReader("1\n2\nx\n"),
+		strings.NewReader(strings.Repeat("3\n", 100000)))}
+	var got []uint64
+	for n, err := range StreamSize(r, SeqL(Uint[uint64], Byte('\n')), 1<<20) {
+		if err == nil {
+			got = append(got, n)
+			continue
+		}
+		if errors.Is(err, ErrRecordTooLong) || !strings.HasPrefix(err.Error(), "offset 4: ") {
+			t.Fatalf("got error %v, want one at offset 4", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("got %v before the error, want [1 2]", got)
+		}
+		if r.n > 4096 {
+			t.Errorf("read %d bytes to find the error", r.n)
+		}
+		return
+	}
+	t.Fatal("no error")
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestLines(t *testing.T) {
+	r := strings.NewReader("1-2\n3_4\n5-6\n7-\n")
+	var (
+		got  []Pair[uint64, uint64]
+		errs []*ParseError
+	)
+	for p, err := range Lines(r, Seq(SeqL(Uint[uint64], Byte('-')), Uint[uint64])) {
+		if err != nil {
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("got error %v, want a *ParseError", err)
+			}
+			errs = append(errs, pe)
+			continue
+		}
+		got = append(got, p)
+	}
+	if len(got) != 2 || got[1].First != 5 || got[1].Second != 6 {
+		t.Errorf("got %v, want lines one and three", got)
+	}
+	if len(errs) != 2 || errs[0].Line != 2 || errs[0].Column != 2 || errs[1].Line != 4 {
+		t.Errorf("got errors %v, want ones for lines two and four", errs)
+	}
+}
+
+func TestLinesErrorsKeepTheirLines(t *testing.T) {
+	// Enough lines that the scanner refills its buffer many times, which
+	// mustn't change what the errors from earlier lines say.
+	var sb strings.Builder
+	for i := range 5000 {
+		if i%2 == 0 {
+			fmt.Fprintf(&sb, "%d\n", i)
+		} else {
+			fmt.Fprintf(&sb, "bad%d\n", i)
+		}
+	}
+	var errs []error
+	for _, err := range Lines(strings.NewReader(sb.String()), Uint[uint64]) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 2500 {
+		t.Fatalf("got %d errors, want 2500", len(errs))
+	}
+	for i, err := range errs {
+		if want := fmt.Sprintf("found: %q", fmt.Sprintf("bad%d", 2*i+1)); !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %d is %q, want it to say %s", i, err, want)
+		}
+	}
+}
+
+func TestLinesCRLF(t *testing.T) {
+	r := strings.NewReader("1\r\n2\r\nx\r\n3\ny\r\n")
+	var offsets []int
+	for _, err := range Lines(r, Uint[uint64]) {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			offsets = append(offsets, pe.Offset)
+		}
+	}
+	if want := []int{6, 11}; !slices.Equal(offsets, want) {
+		t.Errorf("got errors at offsets %v, want %v", offsets, want)
+	}
+}
@@ -0,0 +1,314 @@
+package parse
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Syntax is a grammar that works in both directions: Parser reads an A, and
+// Print appends an A to dst in the same format. Parsing anything that Print
+// produces gives back the value that was printed, so a Syntax can be used to
+// write inputs as well as read them.
+type Syntax[A any] struct {
+	Parser Parser[A]
+	Print  func(dst []byte, a A) ([]byte, error)
+}
+
+// Bytes prints a on its own.
+func (s Syntax[A]) Bytes(a A) ([]byte, error) {
+	return s.Print(nil, a)
+}
+
+// LiteralSyntax is the Syntax for an exact string.
+func LiteralSyntax(lit string) Syntax[struct{}] {
+	return Syntax[struct{}]{
+		Parser: Apply(Literal(lit), func(string) struct{} { return struct{}{} }),
+		Print: func(dst []byte, _ struct{}) ([]byte, error) {
+			return append(dst, lit...), nil
+		},
+	}
+}
+
+// UintSyntax is the Syntax for unsigned decimal integers.
+func UintSyntax[U constraints.Unsigned]() Syntax[U] {
+	return Syntax[U]{
+		Parser: Uint[U],
+		Print: func(dst []byte, u U) ([]byte, error) {
+			return strconv.AppendUint(dst, uint64(u), 10), nil
+		},
+	}
+}
+
+// IntSyntax is the Syntax for signed decimal integers.
+func IntSyntax[S constraints.Signed]() Syntax[S] {
+	return Syntax[S]{
+		Parser: Int[S],
+		Print: func(dst []byte, s S) ([]byte, error) {
+			return strconv.AppendInt(dst, int64(s), 10), nil
+		},
+	}
+}
+
+// SeqSyntax is a followed by b.
+func SeqSyntax[A, B any](a Syntax[A], b Syntax[B]) Syntax[Pair[A, B]] {
+	return Syntax[Pair[A, B]]{
+		Parser: Seq(a.Parser, b.Parser),
+		Print: func(dst []byte, p Pair[A, B]) ([]byte, error) {
+			dst, err := a.Print(dst, p.First)
+			if err != nil {
+				return nil, err
+			}
+			return b.Print(dst, p.Second)
+		},
+	}
+}
+
+// SeqLSyntax is a followed by b, which carries no value so that it can be
+// printed without one.
+func SeqLSyntax[A any](a Syntax[A], b Syntax[struct{}]) Syntax[A] {
+	return MapSyntax(SeqSyntax(a, b),
+		func(p Pair[A, struct{}]) A { return p.First },
+		func(x A) Pair[A, struct{}] { return Pair[A, struct{}]{First: x} },
+	)
+}
+
+// SeqRSyntax is a, which carries no value, followed by b.
+func SeqRSyntax[B any](a Syntax[struct{}], b Syntax[B]) Syntax[B] {
+	return MapSyntax(SeqSyntax(a, b),
+		func(p Pair[struct{}, B]) B { return p.Second },
+		func(x B) Pair[struct{}, B] { return Pair[struct{}, B]{Second: x} },
+	)
+}
+
+// SepBySyntax is one or more a separated by sep. Printing an empty slice is
+// an error, because it couldn't be parsed again.
+func SepBySyntax[A any](a Syntax[A], sep Syntax[struct{}]) Syntax[[]A] {
+	return Syntax[[]A]{
+		Parser: SepBy(a.Parser, sep.Parser),
+		Print: func(dst []byte, as []A) ([]byte, error) {
+			if len(as) == 0 {
+				return nil, errors.New("can't print an empty list")
+			}
+			var err error
+			for i, x := range as {
+				if i > 0 {
+					if dst, err = sep.Print(dst, struct{}{}); err != nil {
+						return nil, err
+					}
+				}
+				if dst, err = a.Print(dst, x); err != nil {
+					return nil, err
+				}
+			}
+			return dst, nil
+		},
+	}
+}
+
+// ManySyntax is any number of a, one after another. What a prints must end
+// where it does when it's parsed again, so numbers need something between
+// them. Printing an element as nothing is an error, because it wouldn't be
+// there to parse.
+func ManySyntax[A any](a Syntax[A]) Syntax[[]A] {
+	return Syntax[[]A]{
+		Parser: Many(a.Parser),
+		Print: func(dst []byte, as []A) ([]byte, error) {
+			for _, x := range as {
+				start := len(dst)
+				var err error
+				if dst, err = a.Print(dst, x); err != nil {
+					return nil, err
+				}
+				if len(dst) == start {
+					return nil, errors.New("can't print an empty element")
+				}
+			}
+			return dst, nil
+		},
+	}
+}
+
+// LinesSyntax is a line for each A, each one ending with a newline. The
+// newline isn't part of line, so a line whose format ends with a string
+// would read on past it: put the newline at the end of the format and use
+// ManySyntax instead.
+func LinesSyntax[A any](line Syntax[A]) Syntax[[]A] {
+	return ManySyntax(SeqLSyntax(line, LiteralSyntax("\n")))
+}
+
+// EnumSyntax is the Syntax for a fixed set of values, each written as its name
+// in names. Longer names are tried first, so that one name can start with
+// another. It panics if two names have the same value, because then it
+// couldn't tell which to print.
+func EnumSyntax[A comparable](names map[string]A) Syntax[A] {
+	tokens := slices.SortedFunc(maps.Keys(names), func(a, b string) int {
+		return cmp.Or(len(b)-len(a), cmp.Compare(a, b))
+	})
+	var (
+		ps      = make([]Parser[A], len(tokens))
+		byValue = make(map[A]string, len(names))
+	)
+	for i, t := range tokens {
+		a := names[t]
+		if other, ok := byValue[a]; ok {
+			panic(fmt.Sprintf("parse: EnumSyntax names %q and %q have the same value", other, t))
+		}
+		byValue[a] = t
+		ps[i] = Apply(Literal(t), func(string) A { return a })
+	}
+	return Syntax[A]{
+		Parser: Or(ps...),
+		Print: func(dst []byte, a A) ([]byte, error) {
+			name, ok := byValue[a]
+			if !ok {
+				return nil, fmt.Errorf("%v has no name", a)
+			}
+			return append(dst, name...), nil
+		},
+	}
+}
+
+// MapSyntax converts a Syntax for A into one for B, given functions to
+// convert both ways which must be each other's inverse.
+func MapSyntax[A, B any](s Syntax[A], to func(A) B, from func(B) A) Syntax[B] {
+	return Syntax[B]{
+		Parser: Apply(s.Parser, to),
+		Print: func(dst []byte, b B) ([]byte, error) {
+			return s.Print(dst, from(b))
+		},
+	}
+}
+
+// FormatSyntax is the Syntax for a format, as described by Format. Values
+// that couldn't be read back, such as strings containing whatever is meant to
+// end them or empty slices without brackets, are an error to print.
+func FormatSyntax[T any](format string) Syntax[T] {
+	p := Format[T](format)
+	printer, err := compileStructPrinter(reflect.TypeFor[T](), format, "")
+	if err != nil {
+		panic(err)
+	}
+	return Syntax[T]{
+		Parser: p,
+		Print: func(dst []byte, t T) ([]byte, error) {
+			return printer(dst, reflect.ValueOf(&t).Elem())
+		},
+	}
+}
+
+// printFunc appends v to dst.
+type printFunc func(dst []byte, v reflect.Value) ([]byte, error)
+
+// compileStructPrinter is the printing equivalent of compileStruct.
+func compileStructPrinter(t reflect.Type, format, stops string) (printFunc, error) {
+	segments, err := structSegments(t, format)
+	if err != nil {
+		return nil, err
+	}
+	var printers []printFunc
+	for i, seg := range segments {
+		if seg.capture == "" {
+			lit := seg.literal
+			printers = append(printers, func(dst []byte, _ reflect.Value) ([]byte, error) {
+				return append(dst, lit...), nil
+			})
+			continue
+		}
+		field, fieldStops, err := captureField(t, segments, i, stops)
+		if err != nil {
+			return nil, err
+		}
+		printer, err := compileValuePrinter(field.Type, field.Tag, 0, fieldStops)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		var (
+			index = field.Index[0]
+			name  = seg.capture
+		)
+		printers = append(printers, func(dst []byte, v reflect.Value) ([]byte, error) {
+			dst, err := printer(dst, v.Field(index))
+			if err != nil {
+				return nil, NewFieldError(name, err)
+			}
+			return dst, nil
+		})
+	}
+	return func(dst []byte, v reflect.Value) ([]byte, error) {
+		var err error
+		for _, p := range printers {
+			if dst, err = p(dst, v); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	}, nil
+}
+
+// compileValuePrinter is the printing equivalent of compileValue.
+func compileValuePrinter(t reflect.Type, tag reflect.StructTag, level int, stops string) (printFunc, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return func(dst []byte, v reflect.Value) ([]byte, error) {
+			s := v.String()
+			if i := strings.IndexAny(s, stops); i != -1 {
+				return nil, fmt.Errorf("%q can't contain %q", s, s[i])
+			}
+			return append(dst, s...), nil
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(dst []byte, v reflect.Value) ([]byte, error) {
+			return strconv.AppendInt(dst, v.Int(), 10), nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(dst []byte, v reflect.Value) ([]byte, error) {
+			return strconv.AppendUint(dst, v.Uint(), 10), nil
+		}, nil
+	case reflect.Struct:
+		return compileStructPrinter(t, tag.Get("format"), stops)
+	case reflect.Slice:
+		return compileSlicePrinter(t, tag, level, stops)
+	}
+	return nil, fmt.Errorf("can't print %v", t)
+}
+
+// compileSlicePrinter is the printing equivalent of compileSlice.
+func compileSlicePrinter(t reflect.Type, tag reflect.StructTag, level int, stops string) (printFunc, error) {
+	layout, err := sliceLayout(tag, level, stops)
+	if err != nil {
+		return nil, err
+	}
+	elem, err := compileValuePrinter(t.Elem(), tag, level+1, layout.elemStops)
+	if err != nil {
+		return nil, err
+	}
+	return func(dst []byte, v reflect.Value) ([]byte, error) {
+		if v.Len() == 0 && layout.opening == nil {
+			return nil, errors.New("can't print an empty list without brackets")
+		}
+		dst = append(dst, layout.opening...)
+		for i := range v.Len() {
+			if i > 0 {
+				dst = append(dst, layout.sep...)
+			}
+			start := len(dst)
+			var err error
+			if dst, err = elem(dst, v.Index(i)); err != nil {
+				return nil, NewFieldError("["+strconv.Itoa(i)+"]", err)
+			}
+			if layout.opening != nil && v.Len() == 1 && len(dst) == start {
+				// This would look like an empty list.
+				return nil, NewFieldError("[0]", errors.New("can't print a single empty element"))
+			}
+		}
+		return append(dst, layout.closing...), nil
+	}, nil
+}
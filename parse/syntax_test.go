@@ -0,0 +1,134 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+type syntaxMachine struct {
+	Lights   string
+	Buttons  [][]uint8 `sep:" " brackets1:"()"`
+	Joltages []int     `brackets:"{}"`
+}
+
+type syntaxPoint struct {
+	X, Y int64
+}
+
+// roundTrip checks that parse(print(x)) == x for random values of A. Values
+// that can't be printed are skipped, but there had better not be too many.
+func roundTrip[A any](t *testing.T, s Syntax[A]) {
+	t.Helper()
+	var printed, skipped int
+	f := func(a A) bool {
+		b, err := s.Bytes(a)
+		if err != nil {
+			skipped++
+			return true
+		}
+		printed++
+		got, err := Run(SeqL(s.Parser, End), b)
+		if err != nil {
+			t.Logf("parsing %q: %v", b, err)
+			return false
+		}
+		if !reflect.DeepEqual(normalise(got), normalise(a)) {
+			t.Logf("parsing %q: got %+v, want %+v", b, got, a)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+	if printed < skipped {
+		t.Errorf("only printed %d values, skipped %d", printed, skipped)
+	}
+}
+
+// normalise makes empty slices nil, since the two print the same.
+func normalise[A any](a A) A {
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Slice:
+			if v.Len() == 0 {
+				v.Set(reflect.Zero(v.Type()))
+			}
+			for i := range v.Len() {
+				walk(v.Index(i))
+			}
+		case reflect.Struct:
+			for i := range v.NumField() {
+				walk(v.Field(i))
+			}
+		}
+	}
+	walk(reflect.ValueOf(&a).Elem())
+	return a
+}
+
+func TestSyntaxRoundTrip(t *testing.T) {
+	t.Run("points", func(t *testing.T) {
+		roundTrip(t, SepBySyntax(FormatSyntax[syntaxPoint]("{x},{y}"), LiteralSyntax("\n")))
+	})
+	t.Run("pairs", func(t *testing.T) {
+		roundTrip(t, SeqSyntax(SeqLSyntax(UintSyntax[uint16](), LiteralSyntax("-")), IntSyntax[int8]()))
+	})
+	t.Run("machines", func(t *testing.T) {
+		roundTrip(t, FormatSyntax[syntaxMachine]("[{lights}] {buttons} {joltages}"))
+	})
+	t.Run("grid", func(t *testing.T) {
+		roundTrip(t, LinesSyntax(ManySyntax(EnumSyntax(map[string]bool{".": false, "@": true}))))
+	})
+}
+
+func TestSyntaxPrint(t *testing.T) {
+	s := FormatSyntax[syntaxMachine]("[{lights}] {buttons} {joltages}")
+	got, err := s.Bytes(syntaxMachine{
+		Lights:   ".##.",
+		Buttons:  [][]uint8{{3}, {1, 3}, {}},
+		Joltages: []int{3, 5, 4, 7},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[.##.] (3) (1,3) () {3,5,4,7}"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := s.Bytes(syntaxMachine{Lights: "]", Buttons: [][]uint8{{1}}}); err == nil {
+		t.Error("printing lights with a ] in them: no error")
+	}
+}
+
+func TestEnumSyntax(t *testing.T) {
+	s := EnumSyntax(map[string]int{"<": 1, "<=": 2, "=": 3})
+	for name, want := range map[string]int{"<": 1, "<=": 2, "=": 3} {
+		got, err := Run(SeqL(s.Parser, End), []byte(name))
+		if err != nil || got != want {
+			t.Errorf("parsing %q: got %d, %v, want %d", name, got, err, want)
+		}
+		b, err := s.Bytes(want)
+		if err != nil || string(b) != name {
+			t.Errorf("printing %d: got %q, %v, want %q", want, b, err, name)
+		}
+	}
+	if _, err := s.Bytes(4); err == nil {
+		t.Error("printing a value without a name: no error")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("two names for one value: no panic")
+		}
+	}()
+	EnumSyntax(map[string]int{"a": 1, "b": 1})
+}
+
+func TestManySyntaxEmpty(t *testing.T) {
+	s := ManySyntax(FormatSyntax[struct{ S string }]("{s}"))
+	if _, err := s.Bytes([]struct{ S string }{{"a"}, {""}}); err == nil {
+		t.Error("printing an empty element: no error")
+	}
+}
@@ -0,0 +1,115 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Label returns a parser that behaves exactly like p, except that errors are
+// prefixed with the name, and that when it's run by RunTrace every call is
+// recorded in the trace.
+func Label[In Input, A any](name string, p ParserOf[In, A]) ParserOf[In, A] {
+	return func(s *State, input In) (ResultOf[In, A], error) {
+		if s == nil || s.trace == nil {
+			r, err := p(s, input)
+			if err != nil {
+				return r, fmt.Errorf("%s: %w", name, err)
+			}
+			return r, nil
+		}
+
+		node := s.trace.push(name, s.offset(len(input)))
+		r, err := p(s, input)
+		s.trace.pop(node, len(input)-len(r.remainder), err)
+		if err != nil {
+			return r, fmt.Errorf("%s: %w", name, err)
+		}
+		return r, nil
+	}
+}
+
+// Trace is a record of the labelled parsers that were run over an input.
+// Only parsers wrapped in Label appear in it: anything else they run is part
+// of their call, so a grammar can be traced in as much detail as it's
+// labelled in.
+type Trace struct {
+	Calls []*TraceCall `json:"calls"`
+
+	stack []*TraceCall
+}
+
+// TraceCall is a single call to a labelled parser. Offset is relative to the
+// start of the whole input, Calls are any labelled parsers that it called in
+// turn.
+type TraceCall struct {
+	Label    string       `json:"label"`
+	Offset   int          `json:"offset"`
+	OK       bool         `json:"ok"`
+	Consumed int          `json:"consumed"`
+	Error    string       `json:"error,omitempty"`
+	Calls    []*TraceCall `json:"calls,omitempty"`
+}
+
+// RunTrace is like Run, but also returns a trace of every labelled parser
+// that was run. The trace is passed along with the run, so other runs of p at
+// the same time, even over the same input, aren't traced.
+func RunTrace[In Input, A any](p ParserOf[In, A], input In) (A, *Trace, error) {
+	s := newState(input)
+	s.trace = &Trace{}
+	a, err := runWith(s, p, input)
+	return a, s.trace, err
+}
+
+func (t *Trace) push(label string, offset int) *TraceCall {
+	c := &TraceCall{
+		Label:  label,
+		Offset: offset,
+	}
+	if n := len(t.stack); n > 0 {
+		parent := t.stack[n-1]
+		parent.Calls = append(parent.Calls, c)
+	} else {
+		t.Calls = append(t.Calls, c)
+	}
+	t.stack = append(t.stack, c)
+	return c
+}
+
+func (t *Trace) pop(c *TraceCall, consumed int, err error) {
+	t.stack = t.stack[:len(t.stack)-1]
+	if err != nil {
+		c.Error = err.Error()
+		return
+	}
+	c.OK = true
+	c.Consumed = consumed
+}
+
+// Print writes the trace as an indented tree, one call per line.
+func (t *Trace) Print(w io.Writer) error {
+	var print func(calls []*TraceCall, depth int) error
+	print = func(calls []*TraceCall, depth int) error {
+		for _, c := range calls {
+			outcome := fmt.Sprintf("ok +%d", c.Consumed)
+			if !c.OK {
+				outcome = "failed: " + c.Error
+			}
+			indent := strings.Repeat("  ", depth)
+			if _, err := fmt.Fprintf(w, "%s%s @%d %s\n", indent, c.Label, c.Offset, outcome); err != nil {
+				return err
+			}
+			if err := print(c.Calls, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return print(t.Calls, 0)
+}
+
+func (t *Trace) String() string {
+	var sb strings.Builder
+	t.Print(&sb)
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+package parse
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRunTrace(t *testing.T) {
+	var (
+		number = Label("number", Uint[uint64])
+		pair   = Label("pair", Seq(SeqL(number, Byte(',')), number))
+		line   = Label("line", Or(pair, Apply(number, func(u uint64) Pair[uint64, uint64] {
+			return Pair[uint64, uint64]{First: u}
+		})))
+	)
+	_, trace, err := RunTrace(line, []byte("12;3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `line @0 ok +2
+  pair @0 failed: expected 44, found: ";3"
+    number @0 ok +2
+  number @0 ok +2
+`
+	if got := trace.String(); got != want {
+		t.Errorf("got trace:\n%s\nwant:\n%s", got, want)
+	}
+
+	raw, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(raw), `{"calls":[{"label":"line","offset":0,"ok":true,"consumed":2,`) {
+		t.Errorf("unexpected JSON: %s", raw)
+	}
+
+	// Outside of RunTrace labels just annotate errors.
+	if _, err := Run(line, []byte("x")); err == nil || !strings.HasPrefix(err.Error(), "line: number: ") {
+		t.Errorf("got error %v, want one prefixed with the labels", err)
+	}
+}
+
+func TestRunTraceConcurrent(t *testing.T) {
+	var (
+		number = Label("number", Uint[uint64])
+		list   = Label("list", SepBy(number, Byte(',')))
+		input  = []byte("1,22,333")
+	)
+	// Traces of pieces of the same input that share an end, and plain
+	// runs over it, shouldn't get mixed up.
+	want := map[int]string{
+		0: "list @0 ok +8\n  number @0 ok +1\n  number @2 ok +2\n  number @5 ok +3\n",
+		2: "list @0 ok +6\n  number @0 ok +2\n  number @3 ok +3\n",
+	}
+	var wg sync.WaitGroup
+	for i := range 32 {
+		wg.Go(func() {
+			for range 50 {
+				if i%3 == 2 {
+					Run(list, input)
+					continue
+				}
+				start := 2 * (i % 3)
+				_, trace, err := RunTrace(list, input[start:])
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if got := trace.String(); got != want[start] {
+					t.Errorf("trace of %q:\n%s\nwant:\n%s", input[start:], got, want[start])
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}